@@ -0,0 +1,129 @@
+package xerrorz
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Based on https://docs.aws.amazon.com/AmazonS3/latest/API/ErrorResponses.html
+type AWSErr struct {
+	XMLName   xml.Name `xml:"Error"`
+	Code      string   `xml:"Code"`                // NoSuchKey, AccessDenied,...
+	Message   string   `xml:"Message"`             // {description}
+	Resource  string   `xml:"Resource,omitempty"`  // InnerErr.Location such as {bucket}/{key}
+	RequestID string   `xml:"RequestId,omitempty"` // {requestId}
+}
+
+// AWSEncoder renders S3-style xml errors.
+// Code is looked up from Codes by the Reason of the first InnerErr, then from the preset table by ErrType,
+// and falls back to the ErrType name.
+type AWSEncoder struct {
+	Codes map[string]string // InnerErr.Reason -> Code
+}
+
+func (AWSEncoder) ContentType() string {
+	return "application/xml"
+}
+
+func (enc AWSEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(enc.AWSErr(e))
+}
+
+// AWSErr maps an HTTPErr into an S3-style error
+func (enc AWSEncoder) AWSErr(e *HTTPErr) *AWSErr {
+	res := &AWSErr{
+		Code:      enc.code(e),
+		Message:   e.ErrDoc.Message,
+		RequestID: e.ErrDoc.RequestID}
+	if res.RequestID == "" {
+		res.RequestID = e.ErrDoc.ID
+	}
+	if len(e.ErrDoc.Errors) > 0 {
+		res.Message = e.ErrDoc.Errors[0].Message
+		res.Resource = e.ErrDoc.Errors[0].Location
+	}
+	return res
+}
+
+// Decode decodes an S3-style xml error responded with the status
+func (enc AWSEncoder) Decode(status int, b []byte) (*HTTPErr, error) {
+	var a AWSErr
+	if err := xml.Unmarshal(b, &a); err != nil {
+		return nil, err
+	}
+
+	errType := enc.errType(status, a.Code)
+	if status == 0 {
+		status = errs[errType].Code
+	}
+	reason := a.Code
+	for r, code := range enc.Codes {
+		if code == a.Code {
+			reason = r
+			break
+		}
+	}
+	return &HTTPErr{
+		ErrDoc: HTTPErrDoc{
+			Errors: []*InnerErr{&InnerErr{
+				Reason:   reason,
+				Location: a.Resource,
+				Message:  a.Message}},
+			Code:    status,
+			Message: errs[errType].Message},
+		Type: errType}, nil
+}
+
+func (enc AWSEncoder) code(e *HTTPErr) string {
+	if len(e.ErrDoc.Errors) > 0 {
+		if code, ok := enc.Codes[e.ErrDoc.Errors[0].Reason]; ok {
+			return code
+		}
+	}
+	if code, ok := awsCodes[e.errType()]; ok {
+		return code
+	}
+	return e.errType().String()
+}
+
+func (enc AWSEncoder) errType(status int, code string) ErrType {
+	if errType, ok := ParseErrType(code); ok {
+		return errType
+	}
+	byStatus := ErrTypeForStatus(status)
+	if awsCodes[byStatus] == code {
+		return byStatus
+	}
+	for i := 0; i < len(errs); i++ {
+		errType := ErrType(i)
+		if awsCodes[errType] == code && (status == 0 || errs[errType].Code == status) {
+			return errType
+		}
+	}
+	return byStatus
+}
+
+var awsCodes = map[ErrType]string{
+	BadRequest:                   "InvalidRequest",
+	InvalidAltVaule:              "InvalidArgument",
+	InvalidArgument:              "InvalidArgument",
+	InvalidParameter:             "InvalidArgument",
+	ParseError:                   "MalformedXML",
+	AuthenticationError:          "MissingSecurityHeader",
+	NotAuthenticated:             "SignatureDoesNotMatch",
+	NotAuthorized:                "AccessDenied",
+	AccountDisabled:              "AccountProblem",
+	Forbidden:                    "AccessDenied",
+	InsufficientPermissions:      "AccessDenied",
+	NotFound:                     "NoSuchKey",
+	LengthRequired:               "MissingContentLength",
+	ConditionNotMet:              "PreconditionFailed",
+	PayloadTooLarge:              "EntityTooLarge",
+	RequestedRangeNotSatisfiable: "InvalidRange",
+	RateLimitExceeded:            "SlowDown",
+	UserRateLimitExceeded:        "SlowDown",
+	InternalServerError:          "InternalError",
+}
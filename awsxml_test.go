@@ -0,0 +1,71 @@
+package xerrorz
+
+import (
+	"bytes"
+	"testing"
+)
+
+const sampleAWSXML = `<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message><Resource>foo/bar.txt</Resource><RequestId>0123456789abcdef0123456789abcdef</RequestId></Error>`
+
+func TestAWSEncoder0(t *testing.T) {
+	errRes := NewHTTPErr(NotFound,
+		NewInnerErr("s3", "noSuchKey", "foo/bar.txt", "path", "The specified key does not exist.", nil))
+
+	var buf bytes.Buffer
+	if err := (AWSEncoder{}).Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	if buf.String() != sampleAWSXML {
+		t.Fatalf("Inconsistent xml was generated: %s\n", buf.String())
+	}
+}
+
+func TestAWSEncoder1(t *testing.T) {
+	// Reason -> Code mapping
+	enc := AWSEncoder{Codes: map[string]string{"noSuchBucket": "NoSuchBucket"}}
+	errRes := NewHTTPErr(NotFound,
+		NewInnerErr("s3", "noSuchBucket", "foo", "path", "The specified bucket does not exist", nil))
+
+	if code := enc.AWSErr(errRes).Code; code != "NoSuchBucket" {
+		t.Fatalf("Invalid code: %s\n", code)
+	}
+	if code := enc.AWSErr(NewHTTPErr(Conflict)).Code; code != "Conflict" {
+		t.Fatalf("Invalid code: %s\n", code)
+	}
+}
+
+func TestAWSEncoder2(t *testing.T) {
+	enc := AWSEncoder{Codes: map[string]string{"noSuchKey": "NoSuchKey"}}
+	errRes, err := enc.Decode(404, []byte(sampleAWSXML))
+	if err != nil {
+		t.Fatalf("Failed to decode an err xml: %+v\n", err)
+	}
+
+	if errRes.Type != NotFound || errRes.ErrDoc.Code != 404 {
+		t.Fatalf("Invalid type or status: %s, %d\n", errRes.Type, errRes.ErrDoc.Code)
+	}
+	iErr := errRes.ErrDoc.Errors[0]
+	if iErr.Reason != "noSuchKey" || iErr.Location != "foo/bar.txt" || iErr.Message != "The specified key does not exist." {
+		t.Fatalf("Invalid inner error: %+v\n", iErr)
+	}
+}
+
+func TestAWSEncoder3(t *testing.T) {
+	// Ambiguous code is resolved by the status
+	errRes, err := (AWSEncoder{}).Decode(400, []byte(`<Error><Code>InvalidArgument</Code><Message>m</Message></Error>`))
+	if err != nil {
+		t.Fatalf("Failed to decode an err xml: %+v\n", err)
+	}
+	if errRes.Type != InvalidArgument {
+		t.Fatalf("Invalid type: %s\n", errRes.Type)
+	}
+
+	errRes, err = (AWSEncoder{}).Decode(403, []byte(`<Error><Code>AccessDenied</Code><Message>m</Message></Error>`))
+	if err != nil {
+		t.Fatalf("Failed to decode an err xml: %+v\n", err)
+	}
+	if errRes.Type != Forbidden {
+		t.Fatalf("Invalid type: %s\n", errRes.Type)
+	}
+}
@@ -0,0 +1,129 @@
+package xerrorz
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// causeEntry is a single error in a chain with the frame printed by its FormatError if any
+type causeEntry struct {
+	Message  string
+	Function string
+	File     string
+	Line     int
+	Stack    string // Goroutine stack of PanicErr, which is not a frame
+}
+
+// causeMessages flattens a cause chain into the message of each error without ones of the following errors
+func causeMessages(err error) []string {
+	res := []string{}
+	for _, entry := range causeEntries(err) {
+		res = append(res, entry.Message)
+	}
+	return res
+}
+
+func causeEntries(err error) []causeEntry {
+	res := []causeEntry{}
+	for err != nil {
+		var entry causeEntry
+		entry, err = formatEntry(err)
+		res = append(res, entry)
+	}
+	return res
+}
+
+// formatEntry formats only err itself and returns the next error
+func formatEntry(err error) (causeEntry, error) {
+	switch pErr := err.(type) {
+	case *PanicErr:
+		return causeEntry{Message: pErr.Error(), Stack: string(pErr.Stack)}, pErr.Unwrap()
+	case PanicErr:
+		return causeEntry{Message: pErr.Error(), Stack: string(pErr.Stack)}, pErr.Unwrap()
+	}
+
+	fErr, ok := err.(xerrors.Formatter)
+	if !ok {
+		return causeEntry{Message: err.Error()}, xerrors.Unwrap(err)
+	}
+
+	p := &entryPrinter{}
+	next := fErr.FormatError(p)
+	entry := causeEntry{
+		Message: strings.TrimSuffix(strings.TrimSpace(p.msg.String()), ":")}
+	entry.Function, entry.File, entry.Line = parseFrame(p.detail.String())
+	return entry, next
+}
+
+// parseFrame parses a frame printed by xerrors.Frame.Format such as "{function}\n    {file}:{line}\n"
+func parseFrame(s string) (function string, file string, line int) {
+	for _, l := range strings.Split(s, "\n") {
+		l = strings.TrimSpace(l)
+		if l == "" {
+			continue
+		}
+		if i := strings.LastIndex(l, ":"); i > 0 {
+			if n, err := strconv.Atoi(l[i+1:]); err == nil {
+				file, line = l[:i], n
+				continue
+			}
+		}
+		if function == "" {
+			function = l
+		}
+	}
+	return function, file, line
+}
+
+// entryPrinter is a xerrors.Printer separately collecting a message and its detail
+type entryPrinter struct {
+	msg      strings.Builder
+	detail   strings.Builder
+	inDetail bool
+}
+
+func (p *entryPrinter) Print(args ...interface{}) {
+	p.writer().WriteString(fmt.Sprint(args...))
+}
+
+func (p *entryPrinter) Printf(format string, args ...interface{}) {
+	p.writer().WriteString(fmt.Sprintf(format, args...))
+}
+
+func (p *entryPrinter) Detail() bool {
+	p.inDetail = true
+	return true
+}
+
+func (p *entryPrinter) writer() *strings.Builder {
+	if p.inDetail {
+		return &p.detail
+	}
+	return &p.msg
+}
+
+// remoteCause reconstructs a cause chain decoded from a foreign error format
+type remoteCause struct {
+	msg   string
+	cause error
+}
+
+func (e remoteCause) Error() string {
+	return e.msg
+}
+
+func (e remoteCause) Format(s fmt.State, v rune) {
+	xerrors.FormatError(e, s, v)
+}
+
+func (e remoteCause) FormatError(p xerrors.Printer) error {
+	p.Print(e.msg)
+	return e.cause
+}
+
+func (e remoteCause) Unwrap() error {
+	return e.cause
+}
@@ -0,0 +1,51 @@
+// Command xerrorz-debugtoken decrypts a debugToken of an error response and prints its causes and frames.
+//
+//	XERRORZ_DEBUG_TOKEN_KEY={hex key} xerrorz-debugtoken {token}
+//	echo {token} | xerrorz-debugtoken -key {hex key}
+package main
+
+import (
+	"bufio"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/amaya382/xerrorz"
+)
+
+const keyEnv = "XERRORZ_DEBUG_TOKEN_KEY"
+
+func main() {
+	keyHex := flag.String("key", os.Getenv(keyEnv), "hex-encoded AES key, "+keyEnv+" by default")
+	flag.Parse()
+
+	key, err := hex.DecodeString(strings.TrimSpace(*keyHex))
+	if err != nil || len(key) == 0 {
+		fail("A hex-encoded key is required by -key or " + keyEnv)
+	}
+
+	token := flag.Arg(0)
+	if token == "" {
+		fmt.Fprintln(os.Stderr, "Paste a debug token:")
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			fail("Failed to read a debug token")
+		}
+		token = line
+	}
+
+	httpErr, err := xerrorz.DecodeDebugToken(key, strings.Trim(strings.TrimSpace(token), `"`))
+	if err != nil {
+		fail(fmt.Sprintf("%v", err))
+	}
+	if err := xerrorz.WriteDebugTree(os.Stdout, httpErr); err != nil {
+		fail(fmt.Sprintf("%v", err))
+	}
+}
+
+func fail(msg string) {
+	fmt.Fprintln(os.Stderr, msg)
+	os.Exit(1)
+}
@@ -0,0 +1,144 @@
+package xerrorz
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// typedErr tags an error with an ErrType
+type typedErr struct {
+	errType ErrType
+	err     error
+	frame   xerrors.Frame
+}
+
+// WithErrType tags err with an ErrType to be rendered by ToHTTPErr
+func WithErrType(err error, errType ErrType) error {
+	if err == nil {
+		return nil
+	}
+	return typedErr{
+		errType: errType,
+		err:     err,
+		frame:   xerrors.Caller(1)}
+}
+
+func (e typedErr) Error() string {
+	return e.err.Error()
+}
+
+func (e typedErr) Format(s fmt.State, v rune) {
+	xerrors.FormatError(e, s, v)
+}
+
+func (e typedErr) FormatError(p xerrors.Printer) error {
+	p.Printf("[%s]", e.errType)
+	e.frame.Format(p)
+	return e.err
+}
+
+func (e typedErr) Unwrap() error {
+	return e.err
+}
+
+// ErrTypeOf finds the ErrType of an HTTPErr or tagged by WithErrType in the chain,
+// which is the one rendered by ToHTTPErr
+func ErrTypeOf(err error) (ErrType, bool) {
+	if httpErr, ok := asHTTPErr(err); ok {
+		return httpErr.errType(), true
+	}
+	var tErr typedErr
+	if xerrors.As(err, &tErr) {
+		return tErr.errType, true
+	}
+	return 0, false
+}
+
+// ToHTTPErr converts any error into an HTTPErr. *HTTPErr in the chain is returned as-is without its wrappers,
+// and is preferred to tags by WithErrType. Errors tagged by WithErrType take the ErrType with its message,
+// and others are InternalServerError.
+func ToHTTPErr(err error) *HTTPErr {
+	if err == nil {
+		return nil
+	}
+	if httpErr, ok := asHTTPErr(err); ok {
+		return httpErr
+	}
+
+	var res *HTTPErr
+	var tErr typedErr
+	if xerrors.As(err, &tErr) {
+		res = NewHTTPErr(tErr.errType,
+			NewInnerErr("global", tErr.errType.reason(), "", "", tErr.err.Error(), err))
+	} else {
+		res = NewHTTPErr(InternalServerError,
+			NewInnerErr("global", InternalServerError.reason(), "", "", errs[InternalServerError].Message, err))
+	}
+	res.frame = xerrors.Caller(1)
+	return res
+}
+
+// StatusHTTPErr builds an HTTPErr for a status written by others, such as http.Error.
+// The ErrType is ErrTypeForStatus(status) while the status is kept, and message becomes an InnerErr if not empty.
+func StatusHTTPErr(status int, message string) *HTTPErr {
+	errType := ErrTypeForStatus(status)
+	var res *HTTPErr
+	if message == "" {
+		res = NewHTTPErr(errType)
+	} else {
+		res = NewHTTPErr(errType, NewInnerErr("global", errType.reason(), "", "", message, nil))
+	}
+	res.ErrDoc.Code = status
+	res.frame = xerrors.Caller(1)
+	return res
+}
+
+func asHTTPErr(err error) (*HTTPErr, bool) {
+	var pErr *HTTPErr
+	if xerrors.As(err, &pErr) && pErr != nil {
+		return pErr, true
+	}
+	var vErr HTTPErr
+	if xerrors.As(err, &vErr) {
+		return &vErr, true
+	}
+	return nil, false
+}
+
+// reason is the lowerCamel name used for InnerErr.Reason such as "invalidArgument"
+func (t ErrType) reason() string {
+	name := t.String()
+	return strings.ToLower(name[:1]) + name[1:]
+}
+
+// MergeHTTPErrs merges HTTPErrs into one. The ErrType of the highest status wins, preferring earlier ones,
+// and InnerErrs and details of all are kept in order. StatusHeaders are merged, taking the longest Retry-After.
+func MergeHTTPErrs(httpErrs ...*HTTPErr) *HTTPErr {
+	var res *HTTPErr
+	for _, httpErr := range httpErrs {
+		if httpErr != nil && (res == nil || httpErr.ErrDoc.Code > res.ErrDoc.Code) {
+			res = httpErr
+		}
+	}
+	if res == nil {
+		return nil
+	}
+
+	merged := *res
+	merged.ErrDoc.Errors = []*InnerErr{}
+	merged.ErrDoc.Details = nil
+	merged.Headers = StatusHeaders{}.merge(res.Headers) // Not to share slices with res
+	for _, httpErr := range httpErrs {
+		if httpErr == nil {
+			continue
+		}
+		merged.ErrDoc.Errors = append(merged.ErrDoc.Errors, httpErr.ErrDoc.Errors...)
+		merged.ErrDoc.Details = append(merged.ErrDoc.Details, httpErr.ErrDoc.Details...)
+		if httpErr != res {
+			merged.Headers = merged.Headers.merge(httpErr.Headers)
+		}
+	}
+	return &merged
+}
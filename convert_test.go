@@ -0,0 +1,136 @@
+package xerrorz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+func TestToHTTPErr0(t *testing.T) {
+	// HTTPErr as-is
+	errRes := NewHTTPErr(NotFound)
+	if ToHTTPErr(errRes) != errRes {
+		t.Fatal("HTTPErr was not returned as-is")
+	}
+	if ToHTTPErr(xerrors.Errorf("wrapped: %w", errRes)) != errRes {
+		t.Fatal("Wrapped HTTPErr was not returned as-is")
+	}
+	if ToHTTPErr(nil) != nil {
+		t.Fatal("nil was converted")
+	}
+
+	// HTTPErr is preferred to tags
+	tagged := WithErrType(errRes, Conflict)
+	if errType, ok := ErrTypeOf(tagged); !ok || errType != NotFound || ToHTTPErr(tagged) != errRes {
+		t.Fatalf("Inconsistent type: %s\n", errType)
+	}
+}
+
+func TestToHTTPErr1(t *testing.T) {
+	// Tagged errors
+	e1 := WithErrType(xerrors.New("id must be positive"), InvalidArgument)
+	e2 := xerrors.Errorf("e2: %w", e1)
+
+	if errType, ok := ErrTypeOf(e2); !ok || errType != InvalidArgument {
+		t.Fatalf("Invalid type: %s\n", errType)
+	}
+
+	errRes := ToHTTPErr(e2)
+	if errRes.Type != InvalidArgument || errRes.ErrDoc.Code != 400 {
+		t.Fatalf("Invalid type or status: %s, %d\n", errRes.Type, errRes.ErrDoc.Code)
+	}
+	iErr := errRes.ErrDoc.Errors[0]
+	if iErr.Reason != "invalidArgument" || iErr.Message != "id must be positive" || iErr.Cause != e2 {
+		t.Fatalf("Invalid inner error: %+v\n", *iErr)
+	}
+
+	errStr := fmt.Sprintf("%+v", errRes)
+	if !strings.Contains(errStr, "[InvalidArgument]") || !strings.Contains(errStr, "TestToHTTPErr1") {
+		t.Fatalf("Invalid format: %s\n", errStr)
+	}
+}
+
+func TestToHTTPErr2(t *testing.T) {
+	// Others
+	errRes := ToHTTPErr(io.ErrUnexpectedEOF)
+	if errRes.Type != InternalServerError {
+		t.Fatalf("Invalid type: %s\n", errRes.Type)
+	}
+	if errRes.ErrDoc.Errors[0].Message != "Internal server error" || errRes.ErrDoc.Errors[0].Cause != io.ErrUnexpectedEOF {
+		t.Fatalf("Invalid inner error: %+v\n", *errRes.ErrDoc.Errors[0])
+	}
+	if _, ok := ErrTypeOf(io.ErrUnexpectedEOF); ok {
+		t.Fatal("Untagged error has a type")
+	}
+	if WithErrType(nil, NotFound) != nil {
+		t.Fatal("nil was tagged")
+	}
+}
+
+func TestMergeHTTPErrs0(t *testing.T) {
+	e1 := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", nil))
+	e2 := NewHTTPErr(NotFound,
+		NewInnerErr("fooService", "notFound", "user", "path", "No such user", nil))
+	e3 := NewHTTPErr(Required,
+		NewInnerErr("fooService", "required", "name", "requestBody", "Name is required", nil))
+
+	merged := MergeHTTPErrs(e1, nil, e2, e3)
+	if merged.Type != NotFound || merged.ErrDoc.Code != 404 {
+		t.Fatalf("Invalid type or status: %s, %d\n", merged.Type, merged.ErrDoc.Code)
+	}
+	if len(merged.ErrDoc.Errors) != 3 || merged.ErrDoc.Errors[0].Location != "id" ||
+		merged.ErrDoc.Errors[2].Location != "name" {
+		t.Fatalf("Invalid inner errors: %+v\n", merged.ErrDoc.Errors)
+	}
+	if len(e2.ErrDoc.Errors) != 1 {
+		t.Fatal("Original HTTPErr was modified")
+	}
+
+	// Earlier one wins with the same status
+	if merged := MergeHTTPErrs(e1, e3); merged.Type != InvalidArgument {
+		t.Fatalf("Invalid type: %s\n", merged.Type)
+	}
+	if MergeHTTPErrs() != nil {
+		t.Fatal("Merged nothing")
+	}
+}
+
+func TestMergeHTTPErrs1(t *testing.T) {
+	// Headers
+	e1 := NewHTTPErr(InvalidArgument)
+	e2 := NewHTTPErr(RateLimitExceeded).WithRetryAfter(3 * time.Second)
+	e3 := NewHTTPErr(MethodNotAllowed).WithAllow("GET", "HEAD")
+	e4 := NewHTTPErr(InternalServerError).WithRetryAfter(time.Second)
+	e5 := NewHTTPErr(MethodNotAllowed).WithAllow("HEAD", "POST")
+
+	merged := MergeHTTPErrs(e1, e2, e3, e4, e5)
+	h := merged.Headers.Header()
+	if merged.Type != InternalServerError || h.Get("Retry-After") != "3" || h.Get("Allow") != "GET, HEAD, POST" {
+		t.Fatalf("Invalid headers: %s, %v\n", merged.Type, h)
+	}
+	if len(e3.Headers.Allow) != 2 || e4.Headers.RetryAfter != time.Second {
+		t.Fatal("Original HTTPErr was modified")
+	}
+}
+
+func TestStatusHTTPErr0(t *testing.T) {
+	httpErr := StatusHTTPErr(503, "Handler timeout")
+	if httpErr.Type != ServiceUnavailable || httpErr.ErrDoc.Code != 503 {
+		t.Fatalf("Invalid type or status: %s, %d\n", httpErr.Type, httpErr.ErrDoc.Code)
+	}
+	if len(httpErr.ErrDoc.Errors) != 1 || httpErr.ErrDoc.Errors[0].Reason != "serviceUnavailable" ||
+		httpErr.ErrDoc.Errors[0].Message != "Handler timeout" {
+		t.Fatalf("Invalid inner errors: %+v\n", httpErr.ErrDoc.Errors)
+	}
+
+	// Unknown status is kept
+	httpErr = StatusHTTPErr(418, "")
+	if httpErr.Type != BadRequest || httpErr.ErrDoc.Code != 418 || len(httpErr.ErrDoc.Errors) != 0 {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+}
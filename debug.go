@@ -0,0 +1,90 @@
+package xerrorz
+
+import (
+	"crypto/subtle"
+	"net/http"
+)
+
+// DebugDoc carries causes and frames, which are NOT included in error jsons unless a DebugPolicy allows
+type DebugDoc struct {
+	Frame  *DebugFrame     `json:"frame,omitempty"` // Where the HTTPErr was created
+	Errors []DebugInnerErr `json:"errors"`          // In the same order as HTTPErrDoc.Errors
+}
+
+type DebugInnerErr struct {
+	Frame  *DebugFrame  `json:"frame,omitempty"` // Where the InnerErr was created
+	Causes []DebugCause `json:"causes"`          // Flattened cause chain
+}
+
+type DebugCause struct {
+	Message string      `json:"message"`
+	Frame   *DebugFrame `json:"frame,omitempty"`
+	Stack   string      `json:"stack,omitempty"` // Goroutine stack of a recovered panic
+}
+
+type DebugFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// DebugPolicy decides whether debug information is rendered for a request. r may be nil.
+type DebugPolicy func(r *http.Request) bool
+
+func NewDebugDoc(e *HTTPErr) *DebugDoc {
+	entry, _ := formatEntry(e)
+	res := &DebugDoc{
+		Frame:  newDebugFrame(entry),
+		Errors: []DebugInnerErr{}}
+	for _, iErr := range e.ErrDoc.Errors {
+		entry, next := formatEntry(iErr)
+		dErr := DebugInnerErr{
+			Frame:  newDebugFrame(entry),
+			Causes: []DebugCause{}}
+		for _, cause := range causeEntries(next) {
+			dErr.Causes = append(dErr.Causes, DebugCause{
+				Message: cause.Message,
+				Frame:   newDebugFrame(cause),
+				Stack:   cause.Stack})
+		}
+		res.Errors = append(res.Errors, dErr)
+	}
+	return res
+}
+
+func newDebugFrame(entry causeEntry) *DebugFrame {
+	if entry.Function == "" && entry.File == "" {
+		return nil
+	}
+	return &DebugFrame{
+		Function: entry.Function,
+		File:     entry.File,
+		Line:     entry.Line}
+}
+
+// DebugAlways enables debug information globally, e.g. on staging servers
+func DebugAlways(r *http.Request) bool {
+	return true
+}
+
+// DebugByHeader enables debug information for requests with a trusted header holding the secret token
+func DebugByHeader(name string, token string) DebugPolicy {
+	return func(r *http.Request) bool {
+		if r == nil || token == "" {
+			return false
+		}
+		return subtle.ConstantTimeCompare([]byte(r.Header.Get(name)), []byte(token)) == 1
+	}
+}
+
+// DebugByAny enables debug information if any of the policies, e.g. a per-client predicate, allows
+func DebugByAny(policies ...DebugPolicy) DebugPolicy {
+	return func(r *http.Request) bool {
+		for _, p := range policies {
+			if p != nil && p(r) {
+				return true
+			}
+		}
+		return false
+	}
+}
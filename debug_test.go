@@ -0,0 +1,91 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestDebugDoc0(t *testing.T) {
+	e1 := xerrors.Errorf("e1: %w", io.ErrClosedPipe)
+	e2 := xerrors.Errorf("e2: %w", e1)
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", e2),
+		NewInnerErr("fooService", "invalidArgument", "name", "requestBody", "Passed name is invalid", nil))
+
+	doc := NewDebugDoc(errRes)
+	if doc.Frame == nil || doc.Frame.Function != "github.com/amaya382/xerrorz.TestDebugDoc0" ||
+		!strings.HasSuffix(doc.Frame.File, "debug_test.go") {
+		t.Fatalf("Invalid frame: %+v\n", doc.Frame)
+	}
+	if len(doc.Errors) != 2 {
+		t.Fatalf("Invalid length: %d\n", len(doc.Errors))
+	}
+
+	causes := doc.Errors[0].Causes
+	if len(causes) != 3 || causes[0].Message != "e2" || causes[1].Message != "e1" ||
+		causes[2].Message != "io: read/write on closed pipe" {
+		t.Fatalf("Invalid causes: %+v\n", causes)
+	}
+	if causes[0].Frame == nil || causes[0].Frame.Line == 0 || causes[2].Frame != nil {
+		t.Fatalf("Invalid cause frames: %+v\n", causes)
+	}
+	if len(doc.Errors[1].Causes) != 0 {
+		t.Fatalf("Invalid causes: %+v\n", doc.Errors[1].Causes)
+	}
+}
+
+func TestRenderer0(t *testing.T) {
+	// Output stays the same without debug policies
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", io.ErrNoProgress))
+	b1, _ := json.Marshal(errRes)
+	b2, _ := json.Marshal((&Renderer{}).Prepare(nil, errRes))
+	if string(b1) != string(b2) {
+		t.Fatalf("Inconsistent json was generated: %s\n", b2)
+	}
+
+	b3, _ := json.Marshal((&Renderer{Debug: DebugAlways}).Prepare(nil, errRes))
+	if !strings.Contains(string(b3), `"debug":{"frame":{"function":"github.com/amaya382/xerrorz.TestRenderer0"`) ||
+		!strings.Contains(string(b3), `"causes":[{"message":"multiple Read calls return no data or error"}]`) {
+		t.Fatalf("No debug object: %s\n", b3)
+	}
+	if errRes.ErrDoc.Debug != nil {
+		t.Fatal("Original HTTPErr was modified")
+	}
+}
+
+func TestRenderer1(t *testing.T) {
+	// Trusted header or client predicate
+	rd := &Renderer{Debug: DebugByAny(
+		DebugByHeader("X-Debug-Token", "secret"),
+		func(r *http.Request) bool { return r != nil && r.RemoteAddr == "10.0.0.1:1234" })}
+	errRes := NewHTTPErr(NotFound)
+
+	r := httptest.NewRequest("GET", "/", nil)
+	if rd.Prepare(r, errRes).ErrDoc.Debug != nil {
+		t.Fatal("Debug object was rendered without the header")
+	}
+	r.Header.Set("X-Debug-Token", "wrong")
+	if rd.Prepare(r, errRes).ErrDoc.Debug != nil {
+		t.Fatal("Debug object was rendered with a wrong token")
+	}
+	r.Header.Set("X-Debug-Token", "secret")
+	if rd.Prepare(r, errRes).ErrDoc.Debug == nil {
+		t.Fatal("Debug object was not rendered with the header")
+	}
+
+	r = httptest.NewRequest("GET", "/", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	if rd.Prepare(r, errRes).ErrDoc.Debug == nil {
+		t.Fatal("Debug object was not rendered for the client")
+	}
+	if rd.Prepare(nil, errRes).ErrDoc.Debug != nil {
+		t.Fatal("Debug object was rendered without a request")
+	}
+}
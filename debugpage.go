@@ -0,0 +1,159 @@
+package xerrorz
+
+import (
+	"html/template"
+	"io"
+	"io/ioutil"
+	"strings"
+)
+
+// DebugHTMLEncoder renders a development error page with every InnerErr, its cause chain,
+// captured frames and surrounding source code read from disk.
+// It is only effective in DevelopmentMode, otherwise Fallback (HTMLEncoder by default) is used.
+type DebugHTMLEncoder struct {
+	Fallback     Encoder
+	ContextLines int // Lines around each frame, 3 if 0
+}
+
+type debugPage struct {
+	Code    int
+	Message string
+	Frame   *debugFrame
+	Errors  []debugInnerErr
+}
+
+type debugInnerErr struct {
+	*InnerErr
+	Frame  *debugFrame
+	Causes []debugCause
+}
+
+type debugCause struct {
+	Message string
+	Frame   *debugFrame
+	Stack   string
+}
+
+type debugFrame struct {
+	*DebugFrame
+	Source []sourceLine
+}
+
+type sourceLine struct {
+	Number  int
+	Text    string
+	Current bool
+}
+
+func (enc DebugHTMLEncoder) ContentType() string {
+	if CurrentMode() != DevelopmentMode {
+		return enc.fallback().ContentType()
+	}
+	return "text/html; charset=utf-8"
+}
+
+func (enc DebugHTMLEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	if CurrentMode() != DevelopmentMode {
+		return enc.fallback().Encode(w, e)
+	}
+	return debugHTML.Execute(w, enc.page(e))
+}
+
+func (enc DebugHTMLEncoder) fallback() Encoder {
+	if enc.Fallback == nil {
+		return HTMLEncoder{}
+	}
+	return enc.Fallback
+}
+
+func (enc DebugHTMLEncoder) page(e *HTTPErr) *debugPage {
+	doc := NewDebugDoc(e)
+	page := &debugPage{
+		Code:    e.ErrDoc.Code,
+		Message: e.ErrDoc.Message,
+		Frame:   enc.frame(doc.Frame)}
+	for i, iErr := range e.ErrDoc.Errors {
+		dErr := debugInnerErr{
+			InnerErr: iErr,
+			Frame:    enc.frame(doc.Errors[i].Frame)}
+		for _, cause := range doc.Errors[i].Causes {
+			dErr.Causes = append(dErr.Causes, debugCause{
+				Message: cause.Message,
+				Frame:   enc.frame(cause.Frame),
+				Stack:   cause.Stack})
+		}
+		page.Errors = append(page.Errors, dErr)
+	}
+	return page
+}
+
+func (enc DebugHTMLEncoder) frame(f *DebugFrame) *debugFrame {
+	if f == nil {
+		return nil
+	}
+	return &debugFrame{
+		DebugFrame: f,
+		Source:     enc.source(f.File, f.Line)}
+}
+
+func (enc DebugHTMLEncoder) source(file string, line int) []sourceLine {
+	b, err := ioutil.ReadFile(file)
+	if err != nil || line <= 0 {
+		return nil
+	}
+	context := enc.ContextLines
+	if context == 0 {
+		context = 3
+	}
+
+	lines := strings.Split(string(b), "\n")
+	res := []sourceLine{}
+	for n := line - context; n <= line+context; n++ {
+		if n < 1 || n > len(lines) {
+			continue
+		}
+		res = append(res, sourceLine{
+			Number:  n,
+			Text:    lines[n-1],
+			Current: n == line})
+	}
+	return res
+}
+
+var debugHTML = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Code}} {{.Message}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #333; }
+h1 { font-size: 1.5em; color: #c00; }
+h2 { font-size: 1.2em; margin-top: 2em; }
+.frame { margin: .5em 0 1em; }
+.location { font-family: monospace; color: #555; }
+pre { background: #f6f6f6; padding: .5em; overflow-x: auto; }
+.current { background: #fdd; }
+.cause { margin-left: 1.5em; border-left: 3px solid #ddd; padding-left: 1em; }
+</style>
+</head>
+<body>
+<h1>{{.Code}} {{.Message}}</h1>
+<p>This page is shown only in development mode.</p>
+{{define "frame"}}{{with .}}<div class="frame">
+<div class="location">{{.Function}} at {{.File}}:{{.Line}}</div>
+{{with .Source}}<pre>{{range .}}<span{{if .Current}} class="current"{{end}}>{{printf "%4d" .Number}}  {{.Text}}</span>
+{{end}}</pre>{{end}}
+</div>{{end}}{{end}}
+{{template "frame" .Frame}}
+{{range .Errors}}<h2>{{.Message}}</h2>
+<p class="location">domain: {{.Domain}}, reason: {{.Reason}}, location: {{.Location}} ({{.LocationType}})</p>
+{{template "frame" .Frame}}
+{{range .Causes}}<div class="cause">
+<p>{{.Message}}</p>
+{{template "frame" .Frame}}
+{{with .Stack}}<pre>{{.}}</pre>{{end}}
+</div>
+{{end}}{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,73 @@
+package xerrorz
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestDebugHTMLEncoder0(t *testing.T) {
+	// Production mode by default
+	e1 := xerrors.Errorf("e1: %w", io.ErrClosedPipe)
+	errRes := NewHTTPErr(InternalServerError,
+		NewInnerErr("fooService", "db", "", "", "Failed to query", e1))
+
+	var buf bytes.Buffer
+	if err := (DebugHTMLEncoder{}).Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	if strings.Contains(buf.String(), "Failed to query") || strings.Contains(buf.String(), "debugpage_test.go") {
+		t.Fatalf("Debug page was rendered in production mode: %s\n", buf.String())
+	}
+}
+
+func TestDebugHTMLEncoder1(t *testing.T) {
+	os.Setenv(ModeEnv, "development")
+	defer os.Unsetenv(ModeEnv)
+	if err := SetMode(DevelopmentMode); err != nil {
+		t.Fatalf("Failed to set mode: %+v\n", err)
+	}
+	defer SetMode(ProductionMode)
+
+	e1 := xerrors.Errorf("e1: %w", io.ErrClosedPipe)
+	e2 := xerrors.Errorf("e2 <b>: %w", e1)
+	errRes := NewHTTPErr(InternalServerError,
+		NewInnerErr("fooService", "db", "", "", "Failed to query", e2))
+
+	var buf bytes.Buffer
+	enc := DebugHTMLEncoder{}
+	if err := enc.Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	page := buf.String()
+
+	for _, s := range []string{
+		"Failed to query", "e2 &lt;b&gt;", "e1", "io: read/write on closed pipe",
+		"TestDebugHTMLEncoder1", "debugpage_test.go:", "errRes := NewHTTPErr(InternalServerError,"} {
+		if !strings.Contains(page, s) {
+			t.Fatalf("Debug page should contain %q: %s\n", s, page)
+		}
+	}
+	if enc.ContentType() != "text/html; charset=utf-8" {
+		t.Fatalf("Invalid content type: %s\n", enc.ContentType())
+	}
+}
+
+func TestDebugHTMLEncoder2(t *testing.T) {
+	// Development mode cannot be enabled without the development env
+	for _, env := range []string{"", "production"} {
+		os.Setenv(ModeEnv, env)
+		if err := SetMode(DevelopmentMode); err == nil {
+			SetMode(ProductionMode)
+			t.Fatalf("Development mode was enabled with %s=%q\n", ModeEnv, env)
+		}
+		if CurrentMode() != ProductionMode {
+			t.Fatal("Mode was changed")
+		}
+	}
+	os.Unsetenv(ModeEnv)
+}
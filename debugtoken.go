@@ -0,0 +1,117 @@
+package xerrorz
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/xerrors"
+)
+
+// Prefix of debug tokens for versioning
+const debugTokenPrefix = "xz1."
+
+// EncodeDebugToken encrypts the full HTTPErr including causes and frames with AES-GCM.
+// key must be 16, 24 or 32 bytes.
+func EncodeDebugToken(key []byte, e *HTTPErr) (string, error) {
+	aead, err := newDebugTokenAEAD(key)
+	if err != nil {
+		return "", err
+	}
+
+	res := *e
+	res.ErrDoc.Debug = NewDebugDoc(e)
+	plain, err := json.Marshal(res)
+	if err != nil {
+		return "", err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, plain, nil)
+	return debugTokenPrefix + base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+// DecodeDebugToken decrypts a debug token into an HTTPErr whose ErrDoc.Debug holds causes and frames
+func DecodeDebugToken(key []byte, token string) (*HTTPErr, error) {
+	aead, err := newDebugTokenAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if !strings.HasPrefix(token, debugTokenPrefix) {
+		return nil, xerrors.New("unknown debug token version")
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(strings.TrimPrefix(token, debugTokenPrefix))
+	if err != nil {
+		return nil, xerrors.Errorf("malformed debug token: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, xerrors.New("malformed debug token: too short")
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, sealed, nil)
+	if err != nil {
+		return nil, xerrors.Errorf("failed to decrypt a debug token: %w", err)
+	}
+	return ParseHTTPErr(plain)
+}
+
+// WriteDebugTree writes the HTTPErr like the `%+v` output using frames in ErrDoc.Debug
+func WriteDebugTree(w io.Writer, e *HTTPErr) error {
+	var b strings.Builder
+	id := ""
+	if e.ErrDoc.ID != "" {
+		id = fmt.Sprintf(" (ID: %s)", e.ErrDoc.ID)
+	}
+	fmt.Fprintf(&b, "[HTTP Status %d] %s%s", e.ErrDoc.Code, e.ErrDoc.Message, id)
+	if e.ErrDoc.RequestID != "" {
+		fmt.Fprintf(&b, " (Request ID: %s)", e.ErrDoc.RequestID)
+	}
+
+	debug := e.ErrDoc.Debug
+	if debug == nil {
+		debug = &DebugDoc{}
+	}
+	writeDebugFrame(&b, debug.Frame)
+	for i, iErr := range e.ErrDoc.Errors {
+		fmt.Fprintf(&b, "\n  - %s", iErr.Message)
+		if i >= len(debug.Errors) {
+			continue
+		}
+		writeDebugFrame(&b, debug.Errors[i].Frame)
+		for _, cause := range debug.Errors[i].Causes {
+			fmt.Fprintf(&b, "\n  - %s", cause.Message)
+			writeDebugFrame(&b, cause.Frame)
+			if cause.Stack != "" {
+				fmt.Fprintf(&b, "\n%s", strings.TrimRight(cause.Stack, "\n"))
+			}
+		}
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeDebugFrame(b *strings.Builder, f *DebugFrame) {
+	if f == nil {
+		return
+	}
+	fmt.Fprintf(b, ":\n    %s\n        %s:%d", f.Function, f.File, f.Line)
+}
+
+func newDebugTokenAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,84 @@
+package xerrorz
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+var sampleKey = []byte("0123456789abcdef0123456789abcdef")
+
+func TestDebugToken0(t *testing.T) {
+	e1 := xerrors.Errorf("e1: %w", io.ErrClosedPipe)
+	errRes := NewHTTPErr(InternalServerError,
+		NewInnerErr("fooService", "db", "", "", "Failed to query", e1))
+
+	token, err := EncodeDebugToken(sampleKey, errRes)
+	if err != nil {
+		t.Fatalf("Failed to encode a debug token: %+v\n", err)
+	}
+	if strings.Contains(token, "Failed to query") {
+		t.Fatalf("Token is not encrypted: %s\n", token)
+	}
+
+	decoded, err := DecodeDebugToken(sampleKey, token)
+	if err != nil {
+		t.Fatalf("Failed to decode a debug token: %+v\n", err)
+	}
+	if decoded.Type != InternalServerError || decoded.ErrDoc.ID != sampleID ||
+		decoded.ErrDoc.Errors[0].Message != "Failed to query" {
+		t.Fatalf("Invalid decoded error: %+v\n", decoded.ErrDoc)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteDebugTree(&buf, decoded); err != nil {
+		t.Fatalf("Failed to write a tree: %+v\n", err)
+	}
+	tree := buf.String()
+	for _, s := range []string{
+		"[HTTP Status 500] Internal server error (ID: " + sampleID + "):",
+		"  - Failed to query:\n    github.com/amaya382/xerrorz.TestDebugToken0\n",
+		"  - e1:\n", "  - io: read/write on closed pipe\n", "debugtoken_test.go:"} {
+		if !strings.Contains(tree, s) {
+			t.Fatalf("Tree should contain %q: %s\n", s, tree)
+		}
+	}
+}
+
+func TestDebugToken1(t *testing.T) {
+	token, err := EncodeDebugToken(sampleKey, NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to encode a debug token: %+v\n", err)
+	}
+
+	if _, err := DecodeDebugToken([]byte("fedcba9876543210fedcba9876543210"), token); err == nil {
+		t.Fatal("Decoded with a wrong key")
+	}
+	if _, err := DecodeDebugToken(sampleKey, token[:len(token)-2]+"AA"); err == nil {
+		t.Fatal("Decoded a tampered token")
+	}
+	if _, err := EncodeDebugToken([]byte("short"), NewHTTPErr(NotFound)); err == nil {
+		t.Fatal("Encoded with an invalid key")
+	}
+}
+
+func TestDebugToken2(t *testing.T) {
+	// Rendered with sanitized messages
+	rd := &Renderer{Sanitize: &SanitizePolicy{}, DebugTokenKey: sampleKey}
+	res := rd.Prepare(nil, NewHTTPErr(InternalServerError,
+		NewInnerErr("fooService", "db", "", "", "pq: connection refused", nil)))
+
+	if res.ErrDoc.DebugToken == "" || strings.Contains(res.ErrDoc.Errors[0].Message, "pq:") {
+		t.Fatalf("Invalid prepared error: %+v\n", res.ErrDoc)
+	}
+	decoded, err := DecodeDebugToken(sampleKey, res.ErrDoc.DebugToken)
+	if err != nil {
+		t.Fatalf("Failed to decode a debug token: %+v\n", err)
+	}
+	if decoded.ErrDoc.Errors[0].Message != "pq: connection refused" {
+		t.Fatalf("Original message was not kept: %+v\n", decoded.ErrDoc.Errors[0])
+	}
+}
@@ -0,0 +1,347 @@
+package xerrorz
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// Based on https://cloud.google.com/apis/design/errors#error_details and
+// https://github.com/googleapis/googleapis/blob/master/google/rpc/error_details.proto
+const detailTypePrefix = "type.googleapis.com/google.rpc."
+
+const (
+	ErrorInfoType           = detailTypePrefix + "ErrorInfo"
+	RetryInfoType           = detailTypePrefix + "RetryInfo"
+	DebugInfoType           = detailTypePrefix + "DebugInfo"
+	QuotaFailureType        = detailTypePrefix + "QuotaFailure"
+	PreconditionFailureType = detailTypePrefix + "PreconditionFailure"
+	BadRequestDetailType    = detailTypePrefix + "BadRequest"
+	ResourceInfoType        = detailTypePrefix + "ResourceInfo"
+	HelpType                = detailTypePrefix + "Help"
+	LocalizedMessageType    = detailTypePrefix + "LocalizedMessage"
+)
+
+// Detail is a typed entry of `details` in an error json, identified by its `@type`
+type Detail interface {
+	TypeURL() string
+}
+
+// Details is a list of Detail encoded with `@type` fields
+type Details []Detail
+
+// RawDetail holds a detail whose `@type` is unknown as-is
+type RawDetail struct {
+	Type string
+	Raw  json.RawMessage
+}
+
+type ErrorInfo struct {
+	Reason   string            `json:"reason"`
+	Domain   string            `json:"domain"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+type RetryInfo struct {
+	RetryDelay time.Duration `json:"-"` // Encoded as a protobuf Duration such as "1.500s"
+}
+
+type DebugInfo struct {
+	StackEntries []string `json:"stackEntries,omitempty"`
+	Detail       string   `json:"detail,omitempty"`
+}
+
+type QuotaFailure struct {
+	Violations []QuotaViolation `json:"violations"`
+}
+
+type QuotaViolation struct {
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+type PreconditionFailure struct {
+	Violations []PreconditionViolation `json:"violations"`
+}
+
+type PreconditionViolation struct {
+	Type        string `json:"type"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+}
+
+// BadRequestDetail is `google.rpc.BadRequest`, renamed not to collide with ErrType BadRequest
+type BadRequestDetail struct {
+	FieldViolations []FieldViolation `json:"fieldViolations"`
+}
+
+type FieldViolation struct {
+	Field       string `json:"field"`
+	Description string `json:"description"`
+}
+
+type ResourceInfo struct {
+	ResourceType string `json:"resourceType"`
+	ResourceName string `json:"resourceName"`
+	Owner        string `json:"owner,omitempty"`
+	Description  string `json:"description"`
+}
+
+type Help struct {
+	Links []HelpLink `json:"links"`
+}
+
+type HelpLink struct {
+	Description string `json:"description"`
+	URL         string `json:"url"`
+}
+
+type LocalizedMessage struct {
+	Locale  string `json:"locale"`
+	Message string `json:"message"`
+}
+
+func (d RawDetail) TypeURL() string           { return d.Type }
+func (d ErrorInfo) TypeURL() string           { return ErrorInfoType }
+func (d RetryInfo) TypeURL() string           { return RetryInfoType }
+func (d DebugInfo) TypeURL() string           { return DebugInfoType }
+func (d QuotaFailure) TypeURL() string        { return QuotaFailureType }
+func (d PreconditionFailure) TypeURL() string { return PreconditionFailureType }
+func (d BadRequestDetail) TypeURL() string    { return BadRequestDetailType }
+func (d ResourceInfo) TypeURL() string        { return ResourceInfoType }
+func (d Help) TypeURL() string                { return HelpType }
+func (d LocalizedMessage) TypeURL() string    { return LocalizedMessageType }
+
+var detailTypes = map[string]func() Detail{
+	ErrorInfoType:           func() Detail { return &ErrorInfo{} },
+	RetryInfoType:           func() Detail { return &RetryInfo{} },
+	DebugInfoType:           func() Detail { return &DebugInfo{} },
+	QuotaFailureType:        func() Detail { return &QuotaFailure{} },
+	PreconditionFailureType: func() Detail { return &PreconditionFailure{} },
+	BadRequestDetailType:    func() Detail { return &BadRequestDetail{} },
+	ResourceInfoType:        func() Detail { return &ResourceInfo{} },
+	HelpType:                func() Detail { return &Help{} },
+	LocalizedMessageType:    func() Detail { return &LocalizedMessage{} },
+}
+
+func NewErrorInfo(reason string, domain string, metadata map[string]string) *ErrorInfo {
+	return &ErrorInfo{
+		Reason:   reason,
+		Domain:   domain,
+		Metadata: metadata}
+}
+
+func NewRetryInfo(retryDelay time.Duration) *RetryInfo {
+	return &RetryInfo{
+		RetryDelay: retryDelay}
+}
+
+func NewDebugInfo(stackEntries []string, detail string) *DebugInfo {
+	return &DebugInfo{
+		StackEntries: stackEntries,
+		Detail:       detail}
+}
+
+func NewQuotaFailure(violations ...QuotaViolation) *QuotaFailure {
+	return &QuotaFailure{
+		Violations: violations}
+}
+
+func NewPreconditionFailure(violations ...PreconditionViolation) *PreconditionFailure {
+	return &PreconditionFailure{
+		Violations: violations}
+}
+
+func NewBadRequestDetail(fieldViolations ...FieldViolation) *BadRequestDetail {
+	return &BadRequestDetail{
+		FieldViolations: fieldViolations}
+}
+
+func NewResourceInfo(resourceType string, resourceName string, owner string,
+	description string) *ResourceInfo {
+	return &ResourceInfo{
+		ResourceType: resourceType,
+		ResourceName: resourceName,
+		Owner:        owner,
+		Description:  description}
+}
+
+func NewHelp(links ...HelpLink) *Help {
+	return &Help{
+		Links: links}
+}
+
+func NewLocalizedMessage(locale string, message string) *LocalizedMessage {
+	return &LocalizedMessage{
+		Locale:  locale,
+		Message: message}
+}
+
+func (d RetryInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		RetryDelay string `json:"retryDelay"`
+	}{formatDuration(d.RetryDelay)})
+}
+
+func (d *RetryInfo) UnmarshalJSON(b []byte) error {
+	var v struct {
+		RetryDelay string `json:"retryDelay"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	delay, err := parseDuration(v.RetryDelay)
+	if err != nil {
+		return err
+	}
+	d.RetryDelay = delay
+	return nil
+}
+
+func (ds Details) MarshalJSON() ([]byte, error) {
+	if ds == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('[')
+	for i, d := range ds {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		b, err := marshalDetail(d)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte(']')
+	return buf.Bytes(), nil
+}
+
+func (ds *Details) UnmarshalJSON(b []byte) error {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(b, &raws); err != nil {
+		return err
+	}
+	if raws == nil {
+		*ds = nil
+		return nil
+	}
+
+	res := make(Details, 0, len(raws))
+	for _, raw := range raws {
+		d, err := ParseDetail(raw)
+		if err != nil {
+			return err
+		}
+		res = append(res, d)
+	}
+	*ds = res
+	return nil
+}
+
+// ParseDetail decodes a single detail object, falling back to RawDetail for unknown `@type`s
+func ParseDetail(b []byte) (Detail, error) {
+	var typed struct {
+		Type string `json:"@type"`
+	}
+	if err := json.Unmarshal(b, &typed); err != nil {
+		return nil, err
+	}
+	if typed.Type == "" {
+		return nil, xerrors.Errorf("detail has no @type: %s", b)
+	}
+
+	newDetail, ok := detailTypes[typed.Type]
+	if !ok {
+		raw := make(json.RawMessage, len(b))
+		copy(raw, b)
+		return &RawDetail{
+			Type: typed.Type,
+			Raw:  raw}, nil
+	}
+	d := newDetail()
+	if err := json.Unmarshal(b, d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// ParseHTTPErr decodes an error json generated from HTTPErr
+func ParseHTTPErr(b []byte) (*HTTPErr, error) {
+	var res HTTPErr
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	res.Type = errTypeOf(res.ErrDoc.Code, res.ErrDoc.Message)
+	return &res, nil
+}
+
+// WithDetails appends details to the error document
+func (e *HTTPErr) WithDetails(details ...Detail) *HTTPErr {
+	e.ErrDoc.Details = append(e.ErrDoc.Details, details...)
+	return e
+}
+
+func marshalDetail(d Detail) ([]byte, error) {
+	switch raw := d.(type) {
+	case RawDetail:
+		return raw.Raw, nil
+	case *RawDetail:
+		return raw.Raw, nil
+	}
+
+	bType, err := json.Marshal(d.TypeURL())
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < 2 || body[0] != '{' {
+		return nil, xerrors.Errorf("detail %s is not encoded as an object", d.TypeURL())
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"@type":`)
+	buf.Write(bType)
+	if len(bytes.TrimSpace(body[1:len(body)-1])) > 0 {
+		buf.WriteByte(',')
+	}
+	buf.Write(body[1:])
+	return buf.Bytes(), nil
+}
+
+// formatDuration follows the json mapping of google.protobuf.Duration
+func formatDuration(d time.Duration) string {
+	sign := ""
+	if d < 0 {
+		sign = "-"
+		d = -d
+	}
+	secs := int64(d / time.Second)
+	nanos := int64(d % time.Second)
+	switch {
+	case nanos == 0:
+		return fmt.Sprintf("%s%ds", sign, secs)
+	case nanos%1e6 == 0:
+		return fmt.Sprintf("%s%d.%03ds", sign, secs, nanos/1e6)
+	case nanos%1e3 == 0:
+		return fmt.Sprintf("%s%d.%06ds", sign, secs, nanos/1e3)
+	default:
+		return fmt.Sprintf("%s%d.%09ds", sign, secs, nanos)
+	}
+}
+
+func parseDuration(s string) (time.Duration, error) {
+	if !strings.HasSuffix(s, "s") {
+		return 0, xerrors.Errorf("invalid duration: %q", s)
+	}
+	return time.ParseDuration(s)
+}
@@ -0,0 +1,125 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+const sampleDetailsJSON = `
+{
+	"error": {
+	  "errors": [],
+	  "code": 429,
+	  "message": "Rate quota was exceeded",
+	  "id": "0123456789abcdef0123456789abcdef",
+	  "details": [
+		{
+		  "@type": "type.googleapis.com/google.rpc.ErrorInfo",
+		  "reason": "RATE_LIMIT_EXCEEDED",
+		  "domain": "fooService",
+		  "metadata": {
+			"quotaLimit": "100"
+		  }
+		},
+		{
+		  "@type": "type.googleapis.com/google.rpc.RetryInfo",
+		  "retryDelay": "1.500s"
+		},
+		{
+		  "@type": "type.googleapis.com/google.rpc.BadRequest",
+		  "fieldViolations": [
+			{
+			  "field": "id",
+			  "description": "Passed id is invalid"
+			}
+		  ]
+		}
+	  ]
+	}
+}`
+
+func TestDetails0(t *testing.T) {
+	errRes := NewHTTPErr(RateLimitExceeded).WithDetails(
+		NewErrorInfo("RATE_LIMIT_EXCEEDED", "fooService", map[string]string{"quotaLimit": "100"}),
+		NewRetryInfo(1500*time.Millisecond),
+		NewBadRequestDetail(FieldViolation{Field: "id", Description: "Passed id is invalid"}))
+	bJSON, err := json.Marshal(errRes)
+	if err != nil {
+		t.Fatalf("Failed to marshal an err object: %+v\n", err)
+	}
+
+	var o1, o2 interface{}
+	err = json.Unmarshal(bJSON, &o1)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal an err json: %+v\n", err)
+	}
+	err = json.Unmarshal([]byte(sampleDetailsJSON), &o2)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal a sample json: %+v\n", err)
+	}
+
+	if !reflect.DeepEqual(o1, o2) {
+		t.Fatalf("Inconsistent json was generated: %s\n", bJSON)
+	}
+}
+
+func TestDetails1(t *testing.T) {
+	// Parse back
+	errRes, err := ParseHTTPErr([]byte(sampleDetailsJSON))
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+
+	details := errRes.ErrDoc.Details
+	if len(details) != 3 {
+		t.Fatalf("Invalid length: %d\n", len(details))
+	}
+
+	info, ok := details[0].(*ErrorInfo)
+	if !ok || info.Reason != "RATE_LIMIT_EXCEEDED" || info.Metadata["quotaLimit"] != "100" {
+		t.Fatalf("Invalid ErrorInfo: %#v\n", details[0])
+	}
+
+	retry, ok := details[1].(*RetryInfo)
+	if !ok || retry.RetryDelay != 1500*time.Millisecond {
+		t.Fatalf("Invalid RetryInfo: %#v\n", details[1])
+	}
+
+	badReq, ok := details[2].(*BadRequestDetail)
+	if !ok || len(badReq.FieldViolations) != 1 || badReq.FieldViolations[0].Field != "id" {
+		t.Fatalf("Invalid BadRequest: %#v\n", details[2])
+	}
+}
+
+func TestDetails2(t *testing.T) {
+	// Unknown @type is kept as-is
+	const raw = `{"@type":"type.googleapis.com/foo.Bar","baz":1}`
+	d, err := ParseDetail([]byte(raw))
+	if err != nil {
+		t.Fatalf("Failed to parse a detail: %+v\n", err)
+	}
+	if d.TypeURL() != "type.googleapis.com/foo.Bar" {
+		t.Fatalf("Invalid type: %s\n", d.TypeURL())
+	}
+
+	bJSON, err := json.Marshal(Details{d})
+	if err != nil {
+		t.Fatalf("Failed to marshal details: %+v\n", err)
+	}
+	if string(bJSON) != "["+raw+"]" {
+		t.Fatalf("Inconsistent json was generated: %s\n", bJSON)
+	}
+}
+
+func TestDetails3(t *testing.T) {
+	// No details, no field
+	bJSON, err := json.Marshal(NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to marshal an err object: %+v\n", err)
+	}
+	if string(bJSON) != `{"error":{"errors":[],"code":404,"message":"Not found","id":"`+sampleID+`"}}` {
+		t.Fatalf("Inconsistent json was generated: %s\n", bJSON)
+	}
+}
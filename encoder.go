@@ -0,0 +1,118 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+// Encoder renders an HTTPErr into a response body of a specific media type
+type Encoder interface {
+	ContentType() string
+	Encode(w io.Writer, e *HTTPErr) error
+}
+
+// StatusEncoder is an Encoder deciding the response status by itself instead of ErrDoc.Code
+type StatusEncoder interface {
+	Encoder
+	Status(e *HTTPErr) int
+}
+
+// JSONEncoder renders the GCP-like error json, the default format
+var JSONEncoder Encoder = jsonEncoder{}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (jsonEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	bJSON, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bJSON)
+	return err
+}
+
+// XMLEncoder renders the error json structure as xml for legacy clients
+var XMLEncoder Encoder = xmlEncoder{}
+
+type xmlEncoder struct{}
+
+func (xmlEncoder) ContentType() string {
+	return "application/xml"
+}
+
+func (xmlEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	return xml.NewEncoder(w).Encode(e)
+}
+
+// TextEncoder renders a compact human-readable text for curl users such as
+//
+//	400 Invalid argument
+//	- fooService.invalidArgument: Passed id is invalid (requestBody: id)
+var TextEncoder Encoder = textEncoder{}
+
+type textEncoder struct{}
+
+func (textEncoder) ContentType() string {
+	return "text/plain; charset=utf-8"
+}
+
+func (textEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d %s\n", e.ErrDoc.Code, e.ErrDoc.Message)
+	for _, iErr := range e.ErrDoc.Errors {
+		b.WriteString("- ")
+		if iErr.Domain != "" || iErr.Reason != "" {
+			fmt.Fprintf(&b, "%s.%s: ", iErr.Domain, iErr.Reason)
+		}
+		b.WriteString(iErr.Message)
+		if iErr.Location != "" || iErr.LocationType != "" {
+			fmt.Fprintf(&b, " (%s: %s)", iErr.LocationType, iErr.Location)
+		}
+		b.WriteString("\n")
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// MSEncoder renders the Microsoft REST API guidelines error json
+type MSEncoder struct {
+	Debug bool // Fold causes into innererror objects
+}
+
+func (MSEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (enc MSEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	bJSON, err := EncodeMSErr(e, enc.Debug)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bJSON)
+	return err
+}
+
+// WithCharset appends charset=utf-8 to textual media types without charset, used by the helpers
+func WithCharset(contentType string) string {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || params["charset"] != "" {
+		return contentType
+	}
+	if strings.HasPrefix(mediaType, "text/") || strings.HasSuffix(mediaType, "/json") ||
+		strings.HasSuffix(mediaType, "+json") || strings.HasSuffix(mediaType, "/xml") ||
+		strings.HasSuffix(mediaType, "+xml") {
+		return contentType + "; charset=utf-8"
+	}
+	return contentType
+}
@@ -47,9 +47,11 @@ func (e ErrQueue) FormatError(p xerrors.Printer) error {
 func (e ErrQueue) Unwrap() error {
 	// Dig the error if a wrapper
 	if wErr, ok := e.Curr.(xerrors.Wrapper); ok {
-		return ErrQueue{
-			Curr:  wErr.Unwrap(),
-			Queue: e.Queue}
+		if next := wErr.Unwrap(); next != nil {
+			return ErrQueue{
+				Curr:  next,
+				Queue: e.Queue}
+		}
 	}
 
 	// Dequeue
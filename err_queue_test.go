@@ -138,3 +138,16 @@ func TestErrQueue4(t *testing.T) {
 		t.Errorf("l.15 should contain \"g1\"\n")
 	}
 }
+
+func TestErrQueue5(t *testing.T) {
+	// Wrappers without causes
+	e1 := NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "e1", nil)
+	var e2 error = NewInnerErr("fooService", "invalidArgument", "name", "requestBody", "e2", nil)
+
+	eq := ErrQueue{Curr: e1, Queue: []*error{&e2}}
+
+	errStr := fmt.Sprintf("%v", eq)
+	if errStr != "e1: e2" {
+		t.Errorf("Invalid error: %s\n", errStr)
+	}
+}
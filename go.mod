@@ -10,4 +10,5 @@ require (
 	golang.org/x/net v0.0.0-20190503192946-f4e77d36d62c // indirect
 	golang.org/x/sys v0.0.0-20190502175342-a43fa875dd82 // indirect
 	golang.org/x/xerrors v0.0.0-20190410155217-1f06c39b4373
+	gopkg.in/go-playground/validator.v8 v8.18.2
 )
@@ -0,0 +1,94 @@
+package xerrorz
+
+import (
+	"net/http"
+	"strings"
+)
+
+// DefaultCacheControl prevents shared caches from storing error responses
+const DefaultCacheControl = "no-store"
+
+// HeaderPolicy adds cache, CORS and security headers to rendered HTTPErrs
+type HeaderPolicy struct {
+	CacheControl map[ErrType]string // Overrides DefaultCacheControl, e.g. "public, max-age=60" for NotFound and Gone
+	CORS         *CORSPolicy        // Disabled if nil
+}
+
+// CORSPolicy exposes error responses to allowed origins, so that browsers can read their bodies
+type CORSPolicy struct {
+	AllowedOrigins   []string // Origins such as "https://example.com", "*" allows any as literal `*`
+	AllowCredentials bool     // Only for origins listed explicitly
+	ExposeHeaders    []string // X-Request-ID and Retry-After are always exposed
+}
+
+// apply sets headers for the request and the prepared HTTPErr. r may be nil.
+func (p *HeaderPolicy) apply(r *http.Request, e *HTTPErr, h http.Header) {
+	cacheControl, ok := p.CacheControl[e.errType()]
+	if !ok {
+		cacheControl = DefaultCacheControl
+	}
+	h.Set("Cache-Control", cacheControl)
+	h.Set("X-Content-Type-Options", "nosniff")
+
+	for _, d := range e.ErrDoc.Details {
+		if lm, ok := localizedMessage(d); ok && lm.Locale != "" {
+			h.Set("Content-Language", lm.Locale)
+			break
+		}
+	}
+
+	if p.CORS != nil && r != nil {
+		p.CORS.apply(r.Header.Get("Origin"), h)
+	}
+}
+
+func (p *CORSPolicy) apply(origin string, h http.Header) {
+	h.Add("Vary", "Origin")
+	if origin == "" {
+		return
+	}
+
+	switch {
+	case p.allowsOrigin(origin):
+		h.Set("Access-Control-Allow-Origin", origin)
+		if p.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+	case p.allowsAny():
+		// Never with credentials, any origin could read responses for the user otherwise
+		h.Set("Access-Control-Allow-Origin", "*")
+	default:
+		return
+	}
+	exposed := append([]string{RequestIDHeader, "Retry-After"}, p.ExposeHeaders...)
+	h.Set("Access-Control-Expose-Headers", strings.Join(exposed, ", "))
+}
+
+// allowsOrigin reports whether the origin is listed explicitly
+func (p *CORSPolicy) allowsOrigin(origin string) bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed != "*" && strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *CORSPolicy) allowsAny() bool {
+	for _, allowed := range p.AllowedOrigins {
+		if allowed == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func localizedMessage(d Detail) (*LocalizedMessage, bool) {
+	switch lm := d.(type) {
+	case *LocalizedMessage:
+		return lm, true
+	case LocalizedMessage:
+		return &lm, true
+	}
+	return nil, false
+}
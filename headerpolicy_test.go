@@ -0,0 +1,101 @@
+package xerrorz
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeaderPolicy0(t *testing.T) {
+	rd := &Renderer{
+		Headers: &HeaderPolicy{
+			CacheControl: map[ErrType]string{NotFound: "public, max-age=60"}}}
+
+	res, err := rd.Render(nil, JSONEncoder, NewHTTPErr(InvalidArgument))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Cache-Control") != "no-store" || res.Header.Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+
+	res, err = rd.Render(nil, JSONEncoder, NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Cache-Control") != "public, max-age=60" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+
+	// Localized
+	res, err = rd.Render(nil, JSONEncoder, NewHTTPErr(InvalidArgument).WithDetails(
+		NewLocalizedMessage("ja-JP", "IDが不正です")))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Content-Language") != "ja-JP" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+}
+
+func TestHeaderPolicy1(t *testing.T) {
+	// CORS
+	rd := &Renderer{
+		Headers: &HeaderPolicy{
+			CORS: &CORSPolicy{
+				AllowedOrigins:   []string{"https://example.com"},
+				AllowCredentials: true}}}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Origin", "https://example.com")
+	res, err := rd.Render(r, JSONEncoder, NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Access-Control-Allow-Origin") != "https://example.com" ||
+		res.Header.Get("Access-Control-Allow-Credentials") != "true" ||
+		res.Header.Get("Access-Control-Expose-Headers") != "X-Request-ID, Retry-After" ||
+		res.Header.Get("Vary") != "Origin" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+
+	// Not allowed
+	r.Header.Set("Origin", "https://evil.example.com")
+	res, err = rd.Render(r, JSONEncoder, NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Access-Control-Allow-Origin") != "" || res.Header.Get("Vary") != "Origin" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+}
+
+func TestHeaderPolicy2(t *testing.T) {
+	// Wildcard never allows credentials
+	rd := &Renderer{
+		Headers: &HeaderPolicy{
+			CORS: &CORSPolicy{
+				AllowedOrigins:   []string{"https://example.com", "*"},
+				AllowCredentials: true}}}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("Origin", "https://evil.example")
+	res, err := rd.Render(r, JSONEncoder, NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Access-Control-Allow-Origin") != "*" ||
+		res.Header.Get("Access-Control-Allow-Credentials") != "" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+
+	// Listed explicitly
+	r.Header.Set("Origin", "https://example.com")
+	res, err = rd.Render(r, JSONEncoder, NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Access-Control-Allow-Origin") != "https://example.com" ||
+		res.Header.Get("Access-Control-Allow-Credentials") != "true" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+}
@@ -0,0 +1,88 @@
+package xerrorz
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StatusHeaders are typed metadata of HTTPErr rendered as headers required by its status
+type StatusHeaders struct {
+	RetryAfter     time.Duration // Retry-After in seconds, for RateLimitExceeded, UserRateLimitExceeded and ServiceUnavailable
+	Challenges     []string      // WWW-Authenticate such as `Bearer realm="example"`, for AuthenticationError and NotAuthenticated
+	Allow          []string      // Allow, for MethodNotAllowed
+	RangeUnit      string        // Content-Range as `{unit} */{length}`, for RequestedRangeNotSatisfiable
+	CompleteLength int64         // {length} of Content-Range
+}
+
+// WithRetryAfter sets the delay rendered as Retry-After
+func (e *HTTPErr) WithRetryAfter(delay time.Duration) *HTTPErr {
+	e.Headers.RetryAfter = delay
+	return e
+}
+
+// WithChallenges appends auth challenges rendered as WWW-Authenticate
+func (e *HTTPErr) WithChallenges(challenges ...string) *HTTPErr {
+	e.Headers.Challenges = append(e.Headers.Challenges, challenges...)
+	return e
+}
+
+// WithAllow appends methods rendered as Allow
+func (e *HTTPErr) WithAllow(methods ...string) *HTTPErr {
+	e.Headers.Allow = append(e.Headers.Allow, methods...)
+	return e
+}
+
+// WithContentRange sets the unit and the complete length rendered as Content-Range such as `bytes */1234`
+func (e *HTTPErr) WithContentRange(unit string, completeLength int64) *HTTPErr {
+	e.Headers.RangeUnit = unit
+	e.Headers.CompleteLength = completeLength
+	return e
+}
+
+// Header returns headers of the set metadata
+func (hs StatusHeaders) Header() http.Header {
+	res := http.Header{}
+	if hs.RetryAfter > 0 {
+		res.Set("Retry-After", strconv.FormatInt(ceilSeconds(hs.RetryAfter), 10))
+	}
+	for _, challenge := range hs.Challenges {
+		res.Add("WWW-Authenticate", challenge)
+	}
+	if len(hs.Allow) > 0 {
+		res.Set("Allow", strings.Join(hs.Allow, ", "))
+	}
+	if hs.RangeUnit != "" {
+		res.Set("Content-Range", fmt.Sprintf("%s */%d", hs.RangeUnit, hs.CompleteLength))
+	}
+	return res
+}
+
+// merge combines metadata of merged HTTPErrs. The longest Retry-After, all challenges and methods are kept,
+// and Content-Range is of hs unless unset.
+func (hs StatusHeaders) merge(other StatusHeaders) StatusHeaders {
+	if other.RetryAfter > hs.RetryAfter {
+		hs.RetryAfter = other.RetryAfter
+	}
+	hs.Challenges = append(hs.Challenges, other.Challenges...)
+	for _, method := range other.Allow {
+		if !containsString(hs.Allow, method) {
+			hs.Allow = append(hs.Allow, method)
+		}
+	}
+	if hs.RangeUnit == "" {
+		hs.RangeUnit, hs.CompleteLength = other.RangeUnit, other.CompleteLength
+	}
+	return hs
+}
+
+func containsString(ss []string, s string) bool {
+	for _, v := range ss {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package xerrorz
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatusHeaders0(t *testing.T) {
+	cases := []struct {
+		httpErr  *HTTPErr
+		expected http.Header
+	}{
+		{NewHTTPErr(RateLimitExceeded).WithRetryAfter(1500 * time.Millisecond),
+			http.Header{"Retry-After": {"2"}}},
+		{NewHTTPErr(AuthenticationError).WithChallenges(`Bearer realm="foo"`, `Basic realm="foo"`),
+			http.Header{"Www-Authenticate": {`Bearer realm="foo"`, `Basic realm="foo"`}}},
+		{NewHTTPErr(MethodNotAllowed).WithAllow("GET", "HEAD"),
+			http.Header{"Allow": {"GET, HEAD"}}},
+		{NewHTTPErr(RequestedRangeNotSatisfiable).WithContentRange("bytes", 1234),
+			http.Header{"Content-Range": {"bytes */1234"}}},
+		{NewHTTPErr(NotFound),
+			http.Header{}}}
+	for _, c := range cases {
+		if h := c.httpErr.Headers.Header(); !reflect.DeepEqual(h, c.expected) {
+			t.Fatalf("Invalid headers for %s: %v\n", c.httpErr.Type, h)
+		}
+	}
+}
+
+func TestStatusHeaders1(t *testing.T) {
+	// Rendered
+	res, err := (&Renderer{}).Render(nil, JSONEncoder, NewHTTPErr(ServiceUnavailable).WithRetryAfter(30*time.Second))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Header.Get("Retry-After") != "30" || res.Header.Get("Content-Type") != "application/json" {
+		t.Fatalf("Invalid headers: %v\n", res.Header)
+	}
+}
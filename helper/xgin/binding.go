@@ -0,0 +1,165 @@
+package xgin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"golang.org/x/xerrors"
+	"gopkg.in/go-playground/validator.v8"
+)
+
+// Tags naming fields and LocationTypes of InnerErrs, keyed by names of bindings
+var (
+	bindingTags = map[string]string{
+		"json":                "json",
+		"xml":                 "xml",
+		"yaml":                "yaml",
+		"form":                "form",
+		"form-urlencoded":     "form",
+		"multipart/form-data": "form",
+		"query":               "form",
+		"uri":                 "uri"}
+	bindingLocationTypes = map[string]string{
+		"form":                "form",
+		"form-urlencoded":     "form",
+		"multipart/form-data": "form",
+		"query":               "query",
+		"uri":                 "path"}
+)
+
+// ShouldBindWith binds obj like c.ShouldBindWith, converting the error by BindErr
+func ShouldBindWith(c *gin.Context, obj interface{}, b binding.Binding) *xerrorz.HTTPErr {
+	return BindErr(c.ShouldBindWith(obj, b), obj, b.Name())
+}
+
+func ShouldBindJSON(c *gin.Context, obj interface{}) *xerrorz.HTTPErr {
+	return BindErr(c.ShouldBindJSON(obj), obj, binding.JSON.Name())
+}
+
+func ShouldBindQuery(c *gin.Context, obj interface{}) *xerrorz.HTTPErr {
+	return BindErr(c.ShouldBindQuery(obj), obj, binding.Query.Name())
+}
+
+func ShouldBindURI(c *gin.Context, obj interface{}) *xerrorz.HTTPErr {
+	return BindErr(c.ShouldBindUri(obj), obj, binding.Uri.Name())
+}
+
+// BindErr converts an error of binding obj by the binding named bindingName.
+// Validation errors become Required if all fields are missing, InvalidParameter otherwise,
+// with one InnerErr per field whose reason is the validation tag. Type mismatches become InvalidParameter,
+// and the others such as malformed bodies become ParseError.
+func BindErr(err error, obj interface{}, bindingName string) *xerrorz.HTTPErr {
+	if err == nil {
+		return nil
+	}
+
+	locationType, ok := bindingLocationTypes[bindingName]
+	if !ok {
+		locationType = "requestBody"
+	}
+
+	if vErrs, ok := err.(validator.ValidationErrors); ok {
+		return validationHTTPErr(vErrs, obj, bindingTags[bindingName], locationType)
+	}
+	var typeErr *json.UnmarshalTypeError
+	if xerrors.As(err, &typeErr) {
+		return xerrorz.NewHTTPErr(xerrorz.InvalidParameter,
+			xerrorz.NewInnerErr("global", "type", typeErr.Field, locationType,
+				fmt.Sprintf("%s must be %s", typeErr.Field, typeErr.Type), err))
+	}
+	return xerrorz.NewHTTPErr(xerrorz.ParseError,
+		xerrorz.NewInnerErr("global", "parseError", "", locationType, err.Error(), err))
+}
+
+func validationHTTPErr(vErrs validator.ValidationErrors, obj interface{}, tag string,
+	locationType string) *xerrorz.HTTPErr {
+	keys := make([]string, 0, len(vErrs))
+	for key := range vErrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	errType := xerrorz.Required
+	innerErrs := make([]*xerrorz.InnerErr, 0, len(keys))
+	for _, key := range keys {
+		fErr := vErrs[key]
+		if fErr.Tag != "required" {
+			errType = xerrorz.InvalidParameter
+		}
+		location := fieldLocation(reflect.TypeOf(obj), fErr.FieldNamespace, tag)
+		innerErrs = append(innerErrs, xerrorz.NewInnerErr("global", fErr.Tag, location, locationType,
+			validationMessage(location, fErr), nil))
+	}
+	return xerrorz.NewHTTPErr(errType, innerErrs...)
+}
+
+func validationMessage(location string, fErr *validator.FieldError) string {
+	if fErr.Tag == "required" {
+		return fmt.Sprintf("%s is required", location)
+	}
+	if fErr.Param != "" {
+		return fmt.Sprintf("%s failed on the '%s=%s' validation", location, fErr.Tag, fErr.Param)
+	}
+	return fmt.Sprintf("%s failed on the '%s' validation", location, fErr.Tag)
+}
+
+// fieldLocation renames a namespace such as `User.Items[0].Name` by tags of fields, e.g. `items[0].name`.
+// Go field names are kept for fields not found or not tagged.
+func fieldLocation(t reflect.Type, namespace string, tag string) string {
+	names := strings.Split(namespace, ".")
+	if len(names) > 1 {
+		names = names[1:] // The first one is the name of the struct
+	}
+
+	res := make([]string, 0, len(names))
+	for _, name := range names {
+		fieldName, index := name, ""
+		if i := strings.IndexByte(name, '['); i >= 0 {
+			fieldName, index = name[:i], name[i:]
+		}
+
+		for t != nil && t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			t = nil
+			res = append(res, name)
+			continue
+		}
+		field, ok := t.FieldByName(fieldName)
+		if !ok {
+			t = nil
+			res = append(res, name)
+			continue
+		}
+
+		res = append(res, taggedName(field, tag)+index)
+		t = field.Type
+		for i := 0; i < strings.Count(index, "["); i++ {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if k := t.Kind(); k == reflect.Slice || k == reflect.Array || k == reflect.Map {
+				t = t.Elem()
+			}
+		}
+	}
+	return strings.Join(res, ".")
+}
+
+func taggedName(field reflect.StructField, tag string) string {
+	if tag == "" {
+		return field.Name
+	}
+	name := strings.Split(field.Tag.Get(tag), ",")[0]
+	if name == "" || name == "-" {
+		return field.Name
+	}
+	return name
+}
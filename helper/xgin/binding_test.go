@@ -0,0 +1,107 @@
+package xgin
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+type sampleItem struct {
+	Name string `json:"itemName" form:"item_name" binding:"required"`
+}
+
+type sampleBody struct {
+	ID    int          `json:"id" form:"id" uri:"id" binding:"required,min=1"`
+	Name  string       `json:"name" form:"name" binding:"required"`
+	Items []sampleItem `json:"items" binding:"dive"`
+}
+
+func newBindContext(method string, target string, body string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(method, target, strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return c
+}
+
+func TestBindErr0(t *testing.T) {
+	// Zero values are missing
+	c := newBindContext("POST", "/", `{"id":0,"items":[{"itemName":"foo"},{}]}`)
+	var body sampleBody
+	httpErr := ShouldBindJSON(c, &body)
+	if httpErr == nil {
+		t.Fatal("No error")
+	}
+	if httpErr.Type != xerrorz.Required {
+		t.Fatalf("Invalid type: %s\n", httpErr.Type)
+	}
+
+	expected := [][3]string{
+		{"required", "id", "requestBody"},
+		{"required", "items[1].itemName", "requestBody"},
+		{"required", "name", "requestBody"}}
+	errs := httpErr.ErrDoc.Errors
+	if len(errs) != len(expected) {
+		t.Fatalf("Invalid inner errors: %+v\n", errs)
+	}
+	for i, e := range expected {
+		if errs[i].Reason != e[0] || errs[i].Location != e[1] || errs[i].LocationType != e[2] {
+			t.Fatalf("Invalid inner error: %+v\n", errs[i])
+		}
+	}
+}
+
+func TestBindErr1(t *testing.T) {
+	// Not missing, but invalid
+	c := newBindContext("GET", "/?id=-1&name=foo", "")
+	var body sampleBody
+	httpErr := ShouldBindQuery(c, &body)
+	if httpErr == nil || httpErr.Type != xerrorz.InvalidParameter {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+	errs := httpErr.ErrDoc.Errors
+	if len(errs) != 1 || errs[0].Reason != "min" || errs[0].Location != "id" || errs[0].LocationType != "query" {
+		t.Fatalf("Invalid inner errors: %+v\n", errs)
+	}
+}
+
+func TestBindErr2(t *testing.T) {
+	// Required only
+	c := newBindContext("GET", "/?id=1", "")
+	var body sampleBody
+	httpErr := ShouldBindQuery(c, &body)
+	if httpErr == nil || httpErr.Type != xerrorz.Required || httpErr.ErrDoc.Errors[0].Location != "name" {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+}
+
+func TestBindErr3(t *testing.T) {
+	// Type mismatch
+	c := newBindContext("POST", "/", `{"id":"foo","name":"bar"}`)
+	var body sampleBody
+	httpErr := ShouldBindJSON(c, &body)
+	if httpErr == nil || httpErr.Type != xerrorz.InvalidParameter {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+	if e := httpErr.ErrDoc.Errors[0]; e.Reason != "type" || e.Location != "id" || e.LocationType != "requestBody" {
+		t.Fatalf("Invalid inner error: %+v\n", e)
+	}
+}
+
+func TestBindErr4(t *testing.T) {
+	// Malformed
+	c := newBindContext("POST", "/", `{"id":`)
+	var body sampleBody
+	httpErr := ShouldBindJSON(c, &body)
+	if httpErr == nil || httpErr.Type != xerrorz.ParseError {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+
+	c = newBindContext("POST", "/", `{"id":1,"name":"foo"}`)
+	if httpErr := ShouldBindJSON(c, &body); httpErr != nil {
+		t.Fatalf("Unexpected error: %+v\n", httpErr)
+	}
+}
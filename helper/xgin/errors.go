@@ -0,0 +1,62 @@
+package xgin
+
+import (
+	"fmt"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorsRenderer is a middleware rendering c.Errors as a single HTTPErr after the handler chain
+type ErrorsRenderer struct {
+	Encoder xerrorz.Encoder                                // xerrorz.JSONEncoder if nil
+	Mapper  func(err error) *xerrorz.HTTPErr               // xerrorz.ToHTTPErr if nil
+	Log     func(c *gin.Context, httpErr *xerrorz.HTTPErr) // Each error is written in `%+v` to gin.DefaultErrorWriter if nil
+}
+
+// Errors renders errors attached by c.Error unless a response was already written.
+// *HTTPErr is passed through, others are converted by xerrorz.ToHTTPErr, and all are merged into one.
+func Errors() gin.HandlerFunc {
+	return ErrorsRenderer{}.Handle
+}
+
+func (er ErrorsRenderer) Handle(c *gin.Context) {
+	c.Next()
+
+	if len(c.Errors) == 0 {
+		return
+	}
+
+	mapper := er.Mapper
+	if mapper == nil {
+		mapper = xerrorz.ToHTTPErr
+	}
+	httpErrs := []*xerrorz.HTTPErr{}
+	for _, ginErr := range c.Errors {
+		httpErr := mapper(ginErr.Err)
+		if httpErr == nil {
+			continue
+		}
+		er.log(c, httpErr)
+		httpErrs = append(httpErrs, httpErr)
+	}
+
+	merged := xerrorz.MergeHTTPErrs(httpErrs...)
+	if merged == nil || c.Writer.Written() {
+		return
+	}
+
+	enc := er.Encoder
+	if enc == nil {
+		enc = xerrorz.JSONEncoder
+	}
+	WriteHTTPErr(c, enc, merged)
+}
+
+func (er ErrorsRenderer) log(c *gin.Context, httpErr *xerrorz.HTTPErr) {
+	if er.Log != nil {
+		er.Log(c, httpErr)
+		return
+	}
+	fmt.Fprintf(gin.DefaultErrorWriter, "[xerrorz] %+v\n", httpErr)
+}
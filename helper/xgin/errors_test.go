@@ -0,0 +1,77 @@
+package xgin
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/xerrors"
+)
+
+func TestErrors0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logged := []string{}
+	r := gin.New()
+	r.Use(ErrorsRenderer{
+		Log: func(c *gin.Context, httpErr *xerrorz.HTTPErr) {
+			logged = append(logged, fmt.Sprintf("%+v", httpErr))
+		}}.Handle)
+	r.GET("/", func(c *gin.Context) {
+		c.Error(xerrorz.NewHTTPErr(xerrorz.InvalidArgument,
+			xerrorz.NewInnerErr("fooService", "invalidArgument", "id", "query", "Passed id is invalid", nil)))
+		c.Error(xerrors.Errorf("failed to query: %w", io.ErrUnexpectedEOF))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "Passed id is invalid") || strings.Contains(w.Body.String(), "failed to query") {
+		t.Fatalf("Invalid body: %s\n", w.Body.String())
+	}
+	if len(logged) != 2 || !strings.Contains(logged[1], "failed to query") {
+		t.Fatalf("Errors were not logged: %v\n", logged)
+	}
+}
+
+func TestErrors1(t *testing.T) {
+	// Already written
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(ErrorsRenderer{Log: func(c *gin.Context, httpErr *xerrorz.HTTPErr) {}}.Handle)
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+		c.Error(xerrorz.NewHTTPErr(xerrorz.NotFound))
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Body.String())
+	}
+}
+
+func TestErrors2(t *testing.T) {
+	// No errors
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Errors())
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Body.String())
+	}
+}
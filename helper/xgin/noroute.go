@@ -0,0 +1,78 @@
+package xgin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleNoRoute replaces the plain text 404 and 405 of the engine with NoRoute and NoMethod,
+// enabling engine.HandleMethodNotAllowed
+func HandleNoRoute(engine *gin.Engine) {
+	engine.HandleMethodNotAllowed = true
+	engine.NoRoute(NoRoute())
+	engine.NoMethod(NoMethod(engine))
+}
+
+// NoRoute renders NotFound with an InnerErr naming the path and the method
+func NoRoute() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		WriteHTTPErr(c, negotiateEncoder(c), xerrorz.NewHTTPErr(xerrorz.NotFound,
+			xerrorz.NewInnerErr("global", "notFound", path, "path",
+				fmt.Sprintf("No route for %s %s", c.Request.Method, path), nil)))
+	}
+}
+
+// NoMethod renders MethodNotAllowed with an InnerErr naming the path and the method.
+// The Allow header lists methods of routes of the engine matching the path.
+func NoMethod(engine *gin.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		httpErr := xerrorz.NewHTTPErr(xerrorz.MethodNotAllowed,
+			xerrorz.NewInnerErr("global", "methodNotAllowed", path, "path",
+				fmt.Sprintf("Method %s is not allowed for %s", c.Request.Method, path), nil))
+		WriteHTTPErr(c, negotiateEncoder(c), httpErr.WithAllow(allowedMethods(engine, path)...))
+	}
+}
+
+func allowedMethods(engine *gin.Engine, path string) []string {
+	seen := map[string]bool{}
+	res := []string{}
+	for _, route := range engine.Routes() {
+		if seen[route.Method] || !matchRoute(route.Path, path) {
+			continue
+		}
+		seen[route.Method] = true
+		res = append(res, route.Method)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// matchRoute matches a path to a route pattern of gin such as `/users/:id/*action`
+func matchRoute(pattern string, path string) bool {
+	pSegs := strings.Split(pattern, "/")
+	segs := strings.Split(path, "/")
+	for i, pSeg := range pSegs {
+		if strings.HasPrefix(pSeg, "*") {
+			return true
+		}
+		if i >= len(segs) {
+			return false
+		}
+		if strings.HasPrefix(pSeg, ":") {
+			if segs[i] == "" {
+				return false
+			}
+			continue
+		}
+		if pSeg != segs[i] {
+			return false
+		}
+	}
+	return len(pSegs) == len(segs)
+}
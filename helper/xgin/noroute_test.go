@@ -0,0 +1,86 @@
+package xgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+func newNoRouteEngine() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	HandleNoRoute(r)
+	ok := func(c *gin.Context) { c.String(http.StatusOK, "ok") }
+	r.GET("/users/:id", ok)
+	r.PUT("/users/:id", ok)
+	r.DELETE("/users/:id", ok)
+	r.POST("/users", ok)
+	r.GET("/files/*path", ok)
+	return r
+}
+
+func TestNoRoute0(t *testing.T) {
+	r := newNoRouteEngine()
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/foo", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.NotFound || httpErr.ErrDoc.Errors[0].Location != "/foo" ||
+		httpErr.ErrDoc.Errors[0].Message != "No route for GET /foo" {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+}
+
+func TestNoMethod0(t *testing.T) {
+	r := newNoRouteEngine()
+	expected := map[string]string{
+		"/users/1":   "DELETE, GET, PUT",
+		"/users":     "POST",
+		"/files/a/b": "GET"}
+	for path, allow := range expected {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("PATCH", path, nil))
+
+		if w.Code != http.StatusMethodNotAllowed {
+			t.Fatalf("Invalid status code for %s: %d\n", path, w.Code)
+		}
+		if w.Header().Get("Allow") != allow {
+			t.Fatalf("Invalid Allow header for %s: %s\n", path, w.Header().Get("Allow"))
+		}
+		httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+		if err != nil {
+			t.Fatalf("Failed to parse an err json: %+v\n", err)
+		}
+		if httpErr.Type != xerrorz.MethodNotAllowed || httpErr.ErrDoc.Errors[0].Location != path {
+			t.Fatalf("Invalid error: %+v\n", httpErr)
+		}
+	}
+}
+
+func TestMatchRoute0(t *testing.T) {
+	cases := []struct {
+		pattern string
+		path    string
+		match   bool
+	}{
+		{"/users/:id", "/users/1", true},
+		{"/users/:id", "/users/", false},
+		{"/users/:id", "/users/1/posts", false},
+		{"/users", "/users", true},
+		{"/files/*path", "/files/a/b", true},
+		{"/files/*path", "/users/a", false}}
+	for _, c := range cases {
+		if matchRoute(c.pattern, c.path) != c.match {
+			t.Fatalf("Invalid match of %s to %s\n", c.path, c.pattern)
+		}
+	}
+}
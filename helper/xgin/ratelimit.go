@@ -0,0 +1,41 @@
+package xgin
+
+import (
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+// RateLimiter is a middleware rendering RateLimitExceeded or UserRateLimitExceeded when Limiter is exhausted
+type RateLimiter struct {
+	Limiter *xerrorz.RateLimiter
+	Encoder xerrorz.Encoder // xerrorz.JSONEncoder if nil
+}
+
+// RateLimit takes a token of limiter per request, setting RateLimit-* headers to every response.
+// The context is aborted if exhausted, and requests are passed if the store of limiter fails.
+func RateLimit(limiter *xerrorz.RateLimiter) gin.HandlerFunc {
+	return RateLimiter{
+		Limiter: limiter}.Handle
+}
+
+func (rl RateLimiter) Handle(c *gin.Context) {
+	h, httpErr, err := rl.Limiter.Take(c.Request)
+	if err != nil {
+		c.Next()
+		return
+	}
+	for k, vs := range h {
+		c.Writer.Header()[k] = vs
+	}
+	if httpErr == nil {
+		c.Next()
+		return
+	}
+
+	enc := rl.Encoder
+	if enc == nil {
+		enc = xerrorz.JSONEncoder
+	}
+	c.Abort()
+	WriteHTTPErr(c, enc, httpErr)
+}
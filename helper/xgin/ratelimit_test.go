@@ -0,0 +1,42 @@
+package xgin
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRateLimit0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := xerrorz.NewRateLimiter(1, 1)
+	limiter.Key = func(r *http.Request) string { return r.Header.Get("X-User") }
+	r := gin.New()
+	r.Use(RateLimit(limiter))
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+	})
+
+	req := httptest.NewRequest("GET", "/", nil)
+	req.Header.Set("X-User", "foo")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || w.Header().Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("Invalid response: %d, %v\n", w.Code, w.Header())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "1" {
+		t.Fatalf("Invalid response: %d, %v\n", w.Code, w.Header())
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.UserRateLimitExceeded {
+		t.Fatalf("Invalid type: %s\n", httpErr.Type)
+	}
+}
@@ -0,0 +1,48 @@
+package xgin
+
+import (
+	"fmt"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+// Recoverer is a middleware rendering panics of the handler chain as InternalServerError
+type Recoverer struct {
+	Encoder xerrorz.Encoder                                // xerrorz.JSONEncoder if nil
+	Report  func(c *gin.Context, httpErr *xerrorz.HTTPErr) // Written in `%+v` to gin.DefaultErrorWriter if nil
+}
+
+// Recovery recovers panics and aborts the context. The cause is xerrorz.PanicErr with the stack,
+// and the response is written only if nothing has been written yet.
+func Recovery() gin.HandlerFunc {
+	return Recoverer{}.Handle
+}
+
+func (rc Recoverer) Handle(c *gin.Context) {
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+
+		httpErr := xerrorz.NewPanicHTTPErr(v)
+		if rc.Report != nil {
+			rc.Report(c, httpErr)
+		} else {
+			fmt.Fprintf(gin.DefaultErrorWriter, "[xerrorz] %+v\n", httpErr)
+		}
+		c.Abort()
+		if c.Writer.Written() {
+			return
+		}
+
+		enc := rc.Encoder
+		if enc == nil {
+			enc = xerrorz.JSONEncoder
+		}
+		WriteHTTPErr(c, enc, httpErr)
+	}()
+
+	c.Next()
+}
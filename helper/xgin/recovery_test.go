@@ -0,0 +1,106 @@
+package xgin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/xerrors"
+)
+
+func TestRecovery0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var reported *xerrorz.HTTPErr
+	after := false
+	r := gin.New()
+	r.Use(Recoverer{
+		Report: func(c *gin.Context, httpErr *xerrorz.HTTPErr) {
+			reported = httpErr
+		}}.Handle)
+	r.GET("/", func(c *gin.Context) {
+		panic(io.ErrUnexpectedEOF)
+	}, func(c *gin.Context) {
+		after = true
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+	if after {
+		t.Fatal("Context was not aborted")
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.InternalServerError {
+		t.Fatalf("Invalid type: %s\n", httpErr.Type)
+	}
+	if reported == nil || !xerrors.Is(reported.ErrDoc.Errors[0].Cause, io.ErrUnexpectedEOF) {
+		t.Fatalf("Panic was not reported: %+v\n", reported)
+	}
+	var panicErr *xerrorz.PanicErr
+	if !xerrors.As(reported.ErrDoc.Errors[0].Cause, &panicErr) || len(panicErr.Stack) == 0 {
+		t.Fatalf("No stack: %+v\n", reported)
+	}
+}
+
+func TestRecovery1(t *testing.T) {
+	// Already written
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Recoverer{Report: func(c *gin.Context, httpErr *xerrorz.HTTPErr) {}}.Handle)
+	r.GET("/", func(c *gin.Context) {
+		c.String(http.StatusOK, "ok")
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Body.String())
+	}
+}
+
+type failingEncoder struct{}
+
+func (failingEncoder) ContentType() string { return "text/html" }
+
+func (failingEncoder) Encode(w io.Writer, e *xerrorz.HTTPErr) error {
+	return io.ErrShortWrite
+}
+
+func TestRecovery2(t *testing.T) {
+	// Rendering failed
+	gin.SetMode(gin.TestMode)
+	var ginErrs []*gin.Error
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		ginErrs = c.Errors
+	}, Recoverer{Encoder: failingEncoder{}, Report: func(c *gin.Context, httpErr *xerrorz.HTTPErr) {}}.Handle)
+	r.GET("/", func(c *gin.Context) {
+		panic("boom")
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError || !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		t.Fatalf("Invalid response: %d, %v\n", w.Code, w.Header())
+	}
+	if _, err := xerrorz.ParseHTTPErr(w.Body.Bytes()); err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if len(ginErrs) != 1 || !xerrors.Is(ginErrs[0].Err, io.ErrShortWrite) {
+		t.Fatalf("Error was not attached: %v\n", ginErrs)
+	}
+}
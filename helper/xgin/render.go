@@ -0,0 +1,47 @@
+package xgin
+
+import (
+	"strings"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+// NegotiatedEncoders are offered to the Accept header by Render, the first one is used if none is acceptable
+var NegotiatedEncoders = []xerrorz.Encoder{
+	xerrorz.JSONEncoder,
+	xerrorz.XMLEncoder,
+	xerrorz.ProblemEncoder{}}
+
+// AbortWithHTTPErr is Render returning the converted HTTPErr
+func AbortWithHTTPErr(c *gin.Context, err error) *xerrorz.HTTPErr {
+	httpErr := xerrorz.ToHTTPErr(err)
+	Render(c, httpErr)
+	return httpErr
+}
+
+// Render aborts the context, attaches err to c.Errors for logging middleware,
+// and writes it converted by xerrorz.ToHTTPErr in a format negotiated by c.NegotiateFormat
+func Render(c *gin.Context, err error) {
+	if err == nil {
+		return
+	}
+	httpErr := xerrorz.ToHTTPErr(err)
+	c.Abort()
+	c.Error(httpErr)
+	WriteHTTPErr(c, negotiateEncoder(c), httpErr)
+}
+
+func negotiateEncoder(c *gin.Context) xerrorz.Encoder {
+	offered := make([]string, len(NegotiatedEncoders))
+	for i, enc := range NegotiatedEncoders {
+		offered[i] = strings.TrimSpace(strings.Split(enc.ContentType(), ";")[0])
+	}
+	format := c.NegotiateFormat(offered...)
+	for i, enc := range NegotiatedEncoders {
+		if offered[i] == format {
+			return enc
+		}
+	}
+	return NegotiatedEncoders[0]
+}
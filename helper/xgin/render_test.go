@@ -0,0 +1,75 @@
+package xgin
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+	"github.com/gin-gonic/gin"
+)
+
+func TestRender0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	expected := map[string]string{
+		"":                                 "application/json; charset=utf-8",
+		"*/*":                              "application/json; charset=utf-8",
+		"application/xml":                  "application/xml; charset=utf-8",
+		"application/problem+json":         "application/problem+json; charset=utf-8",
+		"text/html, application/xml;q=0.9": "application/xml; charset=utf-8",
+		"image/png":                        "application/json; charset=utf-8"}
+	for accept, contentType := range expected {
+		after := false
+		r := gin.New()
+		r.GET("/", func(c *gin.Context) {
+			Render(c, xerrorz.NewHTTPErr(xerrorz.NotFound))
+		}, func(c *gin.Context) {
+			after = true
+		})
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept", accept)
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Fatalf("Invalid status code: %d\n", w.Code)
+		}
+		if w.Header().Get("Content-Type") != contentType {
+			t.Fatalf("Invalid content type for %q: %s\n", accept, w.Header().Get("Content-Type"))
+		}
+		if after {
+			t.Fatal("Context was not aborted")
+		}
+	}
+}
+
+func TestAbortWithHTTPErr0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var ginErrs []*gin.Error
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Next()
+		ginErrs = c.Errors
+	})
+	r.GET("/", func(c *gin.Context) {
+		httpErr := AbortWithHTTPErr(c, io.ErrUnexpectedEOF)
+		if httpErr.Type != xerrorz.InternalServerError {
+			t.Fatalf("Invalid type: %s\n", httpErr.Type)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+	if len(ginErrs) != 1 {
+		t.Fatalf("Error was not attached: %v\n", ginErrs)
+	}
+	if _, ok := ginErrs[0].Err.(*xerrorz.HTTPErr); !ok {
+		t.Fatalf("Invalid attached error: %#v\n", ginErrs[0].Err)
+	}
+}
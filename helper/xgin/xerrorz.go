@@ -1,11 +1,54 @@
 package xgin
 
 import (
+	"net/http"
+
 	"github.com/amaya382/xerrorz"
 	"github.com/gin-gonic/gin"
+	"golang.org/x/xerrors"
 )
 
+// fallbackBody is written when rendering fails, not depending on encoders nor renderer policies
+var fallbackBody = []byte(`{"error":{"errors":[],"code":500,"message":"Internal server error"}}`)
+
 func SetHTTPErrJSON(c *gin.Context, errType xerrorz.ErrType, innerErrs ...*xerrorz.InnerErr) {
-	err := xerrorz.NewHTTPErr(errType, innerErrs...)
-	c.JSON(err.ErrDoc.Code, err)
+	WriteHTTPErr(c, xerrorz.JSONEncoder, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+func SetHTTPErrXML(c *gin.Context, errType xerrorz.ErrType, innerErrs ...*xerrorz.InnerErr) {
+	WriteHTTPErr(c, xerrorz.XMLEncoder, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+func SetHTTPErrText(c *gin.Context, errType xerrorz.ErrType, innerErrs ...*xerrorz.InnerErr) {
+	WriteHTTPErr(c, xerrorz.TextEncoder, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+// SetHTTPErr is SetHTTPErrJSON with an arbitrary encoder such as xerrorz.AWSEncoder
+func SetHTTPErr(c *gin.Context, enc xerrorz.Encoder, errType xerrorz.ErrType, innerErrs ...*xerrorz.InnerErr) {
+	WriteHTTPErr(c, enc, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+// WriteHTTPErr writes an already built HTTPErr with the encoder, applying xerrorz.DefaultRenderer.
+// If rendering fails, a pre-rendered InternalServerError json is written and the error is attached to c.Errors.
+func WriteHTTPErr(c *gin.Context, enc xerrorz.Encoder, httpErr *xerrorz.HTTPErr) {
+	res, err := xerrorz.DefaultRenderer.Render(c.Request, enc, httpErr)
+	if err != nil {
+		res = &xerrorz.Response{
+			Status: http.StatusInternalServerError,
+			Header: http.Header{"Content-Type": {xerrorz.JSONEncoder.ContentType()}},
+			Body:   fallbackBody}
+		c.Error(xerrors.Errorf("failed to render an error response: %w", err))
+	}
+
+	contentType := xerrorz.WithCharset(res.Header.Get("Content-Type"))
+	for k, vs := range res.Header {
+		c.Writer.Header()[k] = vs
+	}
+	c.Header("Content-Type", contentType)
+	c.Data(res.Status, contentType, res.Body)
+}
+
+// WriteJSONRPCErr writes a JSON-RPC response envelope for the request id set in enc
+func WriteJSONRPCErr(c *gin.Context, enc xerrorz.JSONRPCEncoder, httpErr *xerrorz.HTTPErr) {
+	WriteHTTPErr(c, enc, httpErr)
 }
@@ -2,7 +2,9 @@ package xgin
 
 import (
 	"io"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/amaya382/xerrorz"
@@ -62,3 +64,86 @@ func TestSetHTTPErrJSON2(t *testing.T) {
 	// fmt.Println(res.HeaderMap)
 	// fmt.Println(res.Body)
 }
+
+func TestSetHTTPErr0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	SetHTTPErr(c, xerrorz.AWSEncoder{}, xerrorz.NotFound,
+		xerrorz.NewInnerErr("s3", "noSuchKey", "foo/bar.txt", "path", "The specified key does not exist.", nil))
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+
+	if w.Result().Header.Get("Content-Type") != "application/xml; charset=utf-8" {
+		t.Fatalf("Invalid header: Content-Type:%s\n", w.Result().Header.Get("Content-Type"))
+	}
+
+	if !strings.Contains(w.Body.String(), "<Code>NoSuchKey</Code>") {
+		t.Fatalf("Invalid body: %s\n", w.Body.String())
+	}
+}
+
+func TestWriteJSONRPCErr0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	WriteJSONRPCErr(c, xerrorz.JSONRPCEncoder{ID: 1}, xerrorz.NewHTTPErr(xerrorz.InternalServerError))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+
+	if !strings.Contains(w.Body.String(), `"code":-32603`) || !strings.Contains(w.Body.String(), `"id":1`) {
+		t.Fatalf("Invalid body: %s\n", w.Body.String())
+	}
+}
+
+func TestSetHTTPErrXML0(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+
+	SetHTTPErrXML(c, xerrorz.NotFound)
+
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+
+	if !strings.Contains(w.Body.String(), "<code>404</code>") {
+		t.Fatalf("Invalid body: %s\n", w.Body.String())
+	}
+}
+
+func TestSetHTTPErrJSON3(t *testing.T) {
+	// Request ID
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/", nil)
+	c.Request.Header.Set("X-Request-ID", "req-1")
+
+	SetHTTPErrJSON(c, xerrorz.NotFound)
+
+	if w.Result().Header.Get("X-Request-ID") != "req-1" {
+		t.Fatalf("Invalid header: X-Request-ID:%s\n", w.Result().Header.Get("X-Request-ID"))
+	}
+
+	if !strings.Contains(w.Body.String(), `"requestId":"req-1"`) {
+		t.Fatalf("Invalid body: %s\n", w.Body.String())
+	}
+}
+
+func TestSetHTTPErrJSON4(t *testing.T) {
+	// Same Content-Type as c.JSON
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	SetHTTPErrJSON(c, xerrorz.NotFound)
+	if w.Result().Header.Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatalf("Invalid header: Content-Type:%s\n", w.Result().Header.Get("Content-Type"))
+	}
+}
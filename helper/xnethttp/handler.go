@@ -0,0 +1,50 @@
+package xnethttp
+
+import (
+	"net/http"
+
+	"github.com/amaya382/xerrorz"
+)
+
+// ErrHandler is an http.Handler rendering errors returned by Handle, unless Handle already sent headers
+type ErrHandler struct {
+	Handle  func(w http.ResponseWriter, r *http.Request) error
+	Encoder xerrorz.Encoder                  // xerrorz.JSONEncoder if nil
+	Mapper  func(err error) *xerrorz.HTTPErr // xerrorz.ToHTTPErr if nil
+	// Log receives the returned error with the mapped HTTPErr. The `%+v` output of err keeps wrappers,
+	// which are not in the HTTPErr if err wraps an *HTTPErr.
+	Log func(r *http.Request, err error, httpErr *xerrorz.HTTPErr)
+}
+
+// Handler adapts a handler returning errors. *HTTPErr is rendered as-is, errors tagged by xerrorz.WithErrType
+// take the ErrType, and others are rendered as InternalServerError.
+func Handler(handle func(w http.ResponseWriter, r *http.Request) error) http.Handler {
+	return ErrHandler{
+		Handle: handle}
+}
+
+func (h ErrHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tw := &trackingWriter{ResponseWriter: w}
+	err := h.Handle(tw, r)
+	if err == nil {
+		return
+	}
+
+	mapper := h.Mapper
+	if mapper == nil {
+		mapper = xerrorz.ToHTTPErr
+	}
+	httpErr := mapper(err)
+	if httpErr == nil {
+		return
+	}
+	if h.Log != nil {
+		h.Log(r, err, httpErr)
+	}
+
+	enc := h.Encoder
+	if enc == nil {
+		enc = xerrorz.JSONEncoder
+	}
+	RenderHTTPErr(tw, r, enc, httpErr)
+}
@@ -0,0 +1,135 @@
+package xnethttp
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+	"golang.org/x/xerrors"
+)
+
+func TestHandler0(t *testing.T) {
+	// HTTPErr as-is
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return xerrorz.NewHTTPErr(xerrorz.NotFound)
+	})
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+}
+
+func TestHandler1(t *testing.T) {
+	// Tagged error
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return xerrorz.WithErrType(xerrors.New("id must be positive"), xerrorz.InvalidArgument)
+	})
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if res.Code != http.StatusBadRequest {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+	if !strings.Contains(res.Body.String(), `"message":"id must be positive"`) {
+		t.Fatalf("Invalid body: %s\n", res.Body.String())
+	}
+}
+
+func TestHandler2(t *testing.T) {
+	// Others with a mapper and a logger
+	var logged string
+	h := ErrHandler{
+		Handle: func(w http.ResponseWriter, r *http.Request) error {
+			return xerrors.Errorf("failed to read: %w", io.ErrUnexpectedEOF)
+		},
+		Mapper: func(err error) *xerrorz.HTTPErr {
+			if xerrors.Is(err, io.ErrUnexpectedEOF) {
+				return xerrorz.NewHTTPErr(xerrorz.BadGateway,
+					xerrorz.NewInnerErr("global", "upstream", "", "", "Upstream failed", err))
+			}
+			return xerrorz.ToHTTPErr(err)
+		},
+		Log: func(r *http.Request, err error, httpErr *xerrorz.HTTPErr) {
+			logged = fmt.Sprintf("%+v", httpErr)
+		}}
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if res.Code != http.StatusBadGateway {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+	if strings.Contains(res.Body.String(), "failed to read") {
+		t.Fatalf("Cause was leaked: %s\n", res.Body.String())
+	}
+	if !strings.Contains(logged, "failed to read") || !strings.Contains(logged, io.ErrUnexpectedEOF.Error()) {
+		t.Fatalf("Cause was not logged: %s\n", logged)
+	}
+}
+
+func TestHandler3(t *testing.T) {
+	// No error
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("ok"))
+		return nil
+	})
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if res.Code != http.StatusOK || res.Body.String() != "ok" {
+		t.Fatalf("Invalid response: %d, %s\n", res.Code, res.Body.String())
+	}
+}
+
+func TestHandler4(t *testing.T) {
+	// Not rendered after headers were sent
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.Write([]byte("partial"))
+		return io.ErrUnexpectedEOF
+	})
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	if res.Code != http.StatusOK || res.Body.String() != "partial" {
+		t.Fatalf("Invalid response: %d, %s\n", res.Code, res.Body.String())
+	}
+}
+
+func TestHandler5(t *testing.T) {
+	// Rendered after an informational response
+	h := Handler(func(w http.ResponseWriter, r *http.Request) error {
+		w.WriteHeader(103)
+		return xerrorz.NewHTTPErr(xerrorz.NotFound)
+	})
+	res := &informationalRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if len(res.informational) != 1 || res.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status codes: %v, %d\n", res.informational, res.Code)
+	}
+}
+
+func TestHandler6(t *testing.T) {
+	// Wrappers of HTTPErr are logged
+	var logged string
+	h := ErrHandler{
+		Handle: func(w http.ResponseWriter, r *http.Request) error {
+			return xerrors.Errorf("loading user 42: %w", xerrorz.NewHTTPErr(xerrorz.NotFound))
+		},
+		Log: func(r *http.Request, err error, httpErr *xerrorz.HTTPErr) {
+			logged = fmt.Sprintf("%+v", err)
+		}}
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+	if !strings.Contains(logged, "loading user 42") || !strings.Contains(logged, "[HTTP Status 404]") {
+		t.Fatalf("Wrapper was not logged: %s\n", logged)
+	}
+}
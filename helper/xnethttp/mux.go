@@ -0,0 +1,61 @@
+package xnethttp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/amaya382/xerrorz"
+)
+
+// Mux is http.ServeMux rendering its built-in 404 and 405 as NotFound and MethodNotAllowed.
+// The Allow header of 405 is kept.
+type Mux struct {
+	*http.ServeMux
+	Encoder xerrorz.Encoder // xerrorz.JSONEncoder if nil
+}
+
+func NewMux() *Mux {
+	return &Mux{
+		ServeMux: http.NewServeMux()}
+}
+
+func (m *Mux) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if _, pattern := m.ServeMux.Handler(r); pattern != "" {
+		m.ServeMux.ServeHTTP(w, r)
+		return
+	}
+	m.serveFallback(w, r, m.ServeMux)
+}
+
+// serveFallback replaces 404 and 405 written by the fallback handler, others such as redirects are sent as-is
+func (m *Mux) serveFallback(w http.ResponseWriter, r *http.Request, fallback http.Handler) {
+	buf := newResponseBuffer()
+	fallback.ServeHTTP(buf, r)
+
+	var httpErr *xerrorz.HTTPErr
+	switch buf.status {
+	case http.StatusNotFound:
+		httpErr = xerrorz.NewHTTPErr(xerrorz.NotFound,
+			xerrorz.NewInnerErr("global", "notFound", r.URL.Path, "path",
+				fmt.Sprintf("No route for %s %s", r.Method, r.URL.Path), nil))
+	case http.StatusMethodNotAllowed:
+		httpErr = xerrorz.NewHTTPErr(xerrorz.MethodNotAllowed,
+			xerrorz.NewInnerErr("global", "methodNotAllowed", r.URL.Path, "path",
+				fmt.Sprintf("Method %s is not allowed for %s", r.Method, r.URL.Path), nil))
+		for _, method := range strings.Split(buf.header.Get("Allow"), ",") {
+			if method = strings.TrimSpace(method); method != "" {
+				httpErr.WithAllow(method)
+			}
+		}
+	default:
+		buf.flush(w)
+		return
+	}
+
+	enc := m.Encoder
+	if enc == nil {
+		enc = xerrorz.JSONEncoder
+	}
+	RenderHTTPErr(w, r, enc, httpErr)
+}
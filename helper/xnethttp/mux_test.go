@@ -0,0 +1,68 @@
+package xnethttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+)
+
+func TestMux0(t *testing.T) {
+	m := NewMux()
+	m.HandleFunc("/users/", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+
+	// Matched
+	w := httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/users/1", nil))
+	if w.Code != http.StatusOK || w.Body.String() != "ok" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Body.String())
+	}
+
+	// Not found
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/foo", nil))
+	if w.Code != http.StatusNotFound || w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Header().Get("Content-Type"))
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.NotFound || httpErr.ErrDoc.Errors[0].Location != "/foo" {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+
+	// Redirected as-is
+	w = httptest.NewRecorder()
+	m.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+	if w.Code != http.StatusMovedPermanently || w.Header().Get("Location") != "/users/" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Header().Get("Location"))
+	}
+}
+
+func TestMux1(t *testing.T) {
+	// 405 of method patterns, written like Go 1.22+
+	fallback := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+	})
+
+	w := httptest.NewRecorder()
+	NewMux().serveFallback(w, httptest.NewRequest("POST", "/users/1", nil), fallback)
+	if w.Code != http.StatusMethodNotAllowed || w.Header().Get("Allow") != "GET, HEAD" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Header().Get("Allow"))
+	}
+	if w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatalf("Invalid content type: %s\n", w.Header().Get("Content-Type"))
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.MethodNotAllowed {
+		t.Fatalf("Invalid type: %s\n", httpErr.Type)
+	}
+}
@@ -0,0 +1,142 @@
+package xnethttp
+
+import (
+	"bufio"
+	"bytes"
+	"mime"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/amaya382/xerrorz"
+	"golang.org/x/xerrors"
+)
+
+// maxNormalizedBody is the size of error bodies to be buffered, larger ones are sent as-is
+const maxNormalizedBody = 64 << 10
+
+// Normalizer is a middleware rendering error responses written by others, such as http.Error, as HTTPErrs
+type Normalizer struct {
+	Next     http.Handler
+	Encoder  xerrorz.Encoder // xerrorz.JSONEncoder if nil
+	DropText bool            // Not to keep the original text as an InnerErr
+}
+
+// Normalize converts error responses of next whose Content-Type is empty, text/plain or text/html into HTTPErrs
+// by xerrorz.StatusHTTPErr, keeping the status and other headers. Responses rendered by xnethttp,
+// successful ones and flushed ones are passed through.
+func Normalize(next http.Handler) http.Handler {
+	return Normalizer{
+		Next: next}
+}
+
+func (n Normalizer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	nw := &normalizingWriter{ResponseWriter: w}
+	n.Next.ServeHTTP(nw, r)
+	if !nw.buffering {
+		return
+	}
+
+	text := ""
+	if !n.DropText {
+		text = strings.TrimSpace(nw.buf.String())
+	}
+	h := w.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	h.Del("X-Content-Type-Options")
+
+	enc := n.Encoder
+	if enc == nil {
+		enc = xerrorz.JSONEncoder
+	}
+	RenderHTTPErr(w, r, enc, xerrorz.StatusHTTPErr(nw.status, text))
+}
+
+// normalizingWriter buffers error responses not rendered by xnethttp
+type normalizingWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+	rendered    bool
+	buffering   bool
+	status      int
+	buf         bytes.Buffer
+}
+
+func (w *normalizingWriter) headerSent() bool {
+	return w.wroteHeader && !w.buffering
+}
+
+func (w *normalizingWriter) markRendered() {
+	w.rendered = true
+}
+
+func (w *normalizingWriter) WriteHeader(status int) {
+	if w.wroteHeader {
+		return
+	}
+	if status < 200 {
+		// Informational responses such as 103 Early Hints precede the final one
+		w.ResponseWriter.WriteHeader(status)
+		return
+	}
+	w.wroteHeader = true
+	if status >= 400 && !w.rendered && normalizable(w.Header().Get("Content-Type")) {
+		w.buffering = true
+		w.status = status
+		return
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *normalizingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true // Implicit 200 is left to the wrapped writer
+	if !w.buffering {
+		return w.ResponseWriter.Write(b)
+	}
+	if w.buf.Len()+len(b) > maxNormalizedBody {
+		w.passThrough()
+		return w.ResponseWriter.Write(b)
+	}
+	return w.buf.Write(b)
+}
+
+func (w *normalizingWriter) Flush() {
+	if w.buffering {
+		w.passThrough()
+	}
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *normalizingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *normalizingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.New("http.Hijacker is not supported")
+	}
+	return h.Hijack()
+}
+
+// passThrough gives up normalizing, sending the buffered response as-is
+func (w *normalizingWriter) passThrough() {
+	w.buffering = false
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.Write(w.buf.Bytes())
+	w.buf.Reset()
+}
+
+func normalizable(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "text/plain" || mediaType == "text/html"
+}
@@ -0,0 +1,127 @@
+package xnethttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/amaya382/xerrorz"
+)
+
+func TestNormalize0(t *testing.T) {
+	h := Normalize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="foo"`)
+		http.Error(w, "invalid token", http.StatusUnauthorized)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusUnauthorized || w.Header().Get("Content-Type") != "application/json; charset=utf-8" {
+		t.Fatalf("Invalid response: %d, %s\n", w.Code, w.Header().Get("Content-Type"))
+	}
+	if w.Header().Get("WWW-Authenticate") != `Bearer realm="foo"` {
+		t.Fatalf("Invalid headers: %v\n", w.Header())
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.AuthenticationError || httpErr.ErrDoc.Errors[0].Message != "invalid token" {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+}
+
+func TestNormalize1(t *testing.T) {
+	// http.TimeoutHandler
+	h := Normalizer{
+		Next: http.TimeoutHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(50 * time.Millisecond)
+		}), time.Millisecond, "timeout"),
+		DropText: true}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Invalid status code: %d\n", w.Code)
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.ServiceUnavailable || len(httpErr.ErrDoc.Errors) != 0 {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+}
+
+func TestNormalize2(t *testing.T) {
+	// Passed through
+	handlers := map[string]http.HandlerFunc{
+		"ok": func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("ok"))
+		},
+		"json": func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"message":"bad"}`))
+		},
+		"rendered": func(w http.ResponseWriter, r *http.Request) {
+			SetHTTPErrText(w, xerrorz.NotFound)
+		},
+		"flushed": func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte("partial"))
+			w.(http.Flusher).Flush()
+		}}
+	for name, handler := range handlers {
+		expected := httptest.NewRecorder()
+		handler(expected, httptest.NewRequest("GET", "/", nil))
+
+		w := httptest.NewRecorder()
+		Normalize(handler).ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+		if w.Code != expected.Code || w.Body.String() != expected.Body.String() ||
+			w.Header().Get("Content-Type") != expected.Header().Get("Content-Type") {
+			t.Fatalf("%s was modified: %d, %s\n", name, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestNormalize3(t *testing.T) {
+	// After an informational response
+	h := Normalize(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Link", "</style.css>; rel=preload")
+		w.WriteHeader(103)
+		http.Error(w, "nope", http.StatusNotFound)
+	}))
+
+	w := &informationalRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if len(w.informational) != 1 || w.informational[0] != 103 || w.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status codes: %v, %d\n", w.informational, w.Code)
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(w.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.NotFound || httpErr.ErrDoc.Errors[0].Message != "nope" {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+}
+
+// informationalRecorder sends 1xx statuses before the final one like servers, not taking them as the final one
+type informationalRecorder struct {
+	*httptest.ResponseRecorder
+	informational []int
+}
+
+func (w *informationalRecorder) WriteHeader(status int) {
+	if status < 200 {
+		w.informational = append(w.informational, status)
+		return
+	}
+	w.ResponseRecorder.WriteHeader(status)
+}
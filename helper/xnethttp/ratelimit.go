@@ -0,0 +1,43 @@
+package xnethttp
+
+import (
+	"net/http"
+
+	"github.com/amaya382/xerrorz"
+)
+
+// RateLimiter is a middleware rendering RateLimitExceeded or UserRateLimitExceeded when Limiter is exhausted
+type RateLimiter struct {
+	Next    http.Handler
+	Limiter *xerrorz.RateLimiter
+	Encoder xerrorz.Encoder // xerrorz.JSONEncoder if nil
+}
+
+// RateLimit takes a token of limiter per request, setting RateLimit-* headers to every response.
+// Requests are passed to next if the store of limiter fails.
+func RateLimit(next http.Handler, limiter *xerrorz.RateLimiter) http.Handler {
+	return RateLimiter{
+		Next:    next,
+		Limiter: limiter}
+}
+
+func (rl RateLimiter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h, httpErr, err := rl.Limiter.Take(r)
+	if err != nil {
+		rl.Next.ServeHTTP(w, r)
+		return
+	}
+	for k, vs := range h {
+		w.Header()[k] = vs
+	}
+	if httpErr == nil {
+		rl.Next.ServeHTTP(w, r)
+		return
+	}
+
+	enc := rl.Encoder
+	if enc == nil {
+		enc = xerrorz.JSONEncoder
+	}
+	RenderHTTPErr(w, r, enc, httpErr)
+}
@@ -0,0 +1,38 @@
+package xnethttp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+)
+
+func TestRateLimit0(t *testing.T) {
+	h := RateLimit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}), xerrorz.NewRateLimiter(1, 1))
+
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	if res.Code != http.StatusOK || res.Result().Header.Get("RateLimit-Remaining") != "0" {
+		t.Fatalf("Invalid response: %d, %v\n", res.Code, res.Result().Header)
+	}
+
+	res = httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+	if res.Code != http.StatusTooManyRequests || res.Result().Header.Get("Retry-After") != "1" ||
+		res.Result().Header.Get("RateLimit-Limit") != "1" {
+		t.Fatalf("Invalid response: %d, %v\n", res.Code, res.Result().Header)
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(res.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.RateLimitExceeded {
+		t.Fatalf("Invalid type: %s\n", httpErr.Type)
+	}
+	if _, ok := httpErr.ErrDoc.Details[0].(*xerrorz.QuotaFailure); !ok {
+		t.Fatalf("Invalid details: %#v\n", httpErr.ErrDoc.Details)
+	}
+}
@@ -0,0 +1,53 @@
+package xnethttp
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/amaya382/xerrorz"
+)
+
+// Recoverer is a middleware rendering panics of Next as InternalServerError
+type Recoverer struct {
+	Next    http.Handler
+	Encoder xerrorz.Encoder                                 // xerrorz.JSONEncoder if nil
+	Report  func(r *http.Request, httpErr *xerrorz.HTTPErr) // The cause is xerrorz.PanicErr with the stack, logged in `%+v` if nil
+}
+
+// Recover recovers panics in next. The response is written only if headers have not been sent yet.
+// http.ErrAbortHandler is re-panicked to abort the response as net/http does.
+func Recover(next http.Handler) http.Handler {
+	return Recoverer{
+		Next: next}
+}
+
+func (rc Recoverer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	tw := &trackingWriter{ResponseWriter: w}
+	defer func() {
+		v := recover()
+		if v == nil {
+			return
+		}
+		if v == http.ErrAbortHandler {
+			panic(v)
+		}
+
+		httpErr := xerrorz.NewPanicHTTPErr(v)
+		if rc.Report != nil {
+			rc.Report(r, httpErr)
+		} else {
+			log.Printf("%+v", httpErr)
+		}
+		if tw.wroteHeader {
+			return
+		}
+
+		enc := rc.Encoder
+		if enc == nil {
+			enc = xerrorz.JSONEncoder
+		}
+		RenderHTTPErr(w, r, enc, httpErr)
+	}()
+
+	rc.Next.ServeHTTP(tw, r)
+}
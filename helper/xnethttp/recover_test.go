@@ -0,0 +1,82 @@
+package xnethttp
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/amaya382/xerrorz"
+)
+
+func TestRecover0(t *testing.T) {
+	var reported string
+	h := Recoverer{
+		Next: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}),
+		Report: func(r *http.Request, httpErr *xerrorz.HTTPErr) {
+			reported = fmt.Sprintf("%+v", httpErr)
+		}}
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+	if !strings.Contains(res.Body.String(), `"code":500`) || strings.Contains(res.Body.String(), "boom") {
+		t.Fatalf("Invalid body: %s\n", res.Body.String())
+	}
+	if !strings.Contains(reported, "panic: boom") || !strings.Contains(reported, "goroutine") {
+		t.Fatalf("Panic was not reported: %s\n", reported)
+	}
+}
+
+func TestRecover1(t *testing.T) {
+	// Headers were already sent
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("partial"))
+		panic("boom")
+	}))
+	res := httptest.NewRecorder()
+	h.ServeHTTP(res, httptest.NewRequest("GET", "/", nil))
+
+	if res.Code != http.StatusOK || res.Body.String() != "partial" {
+		t.Fatalf("Invalid response: %d, %s\n", res.Code, res.Body.String())
+	}
+}
+
+func TestRecover2(t *testing.T) {
+	// http.ErrAbortHandler
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Fatalf("http.ErrAbortHandler was not re-panicked: %v\n", v)
+		}
+	}()
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+}
+
+func TestRecover3(t *testing.T) {
+	// Logged by default
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+
+	if !strings.Contains(buf.String(), "panic: boom") {
+		t.Fatalf("Panic was not logged: %s\n", buf.String())
+	}
+}
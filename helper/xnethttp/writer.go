@@ -0,0 +1,123 @@
+package xnethttp
+
+import (
+	"bufio"
+	"bytes"
+	"net"
+	"net/http"
+
+	"golang.org/x/xerrors"
+)
+
+// trackingWriter records whether headers were sent
+type trackingWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *trackingWriter) WriteHeader(status int) {
+	if status >= 200 { // Informational responses leave the final one unsent
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *trackingWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *trackingWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+func (w *trackingWriter) headerSent() bool {
+	return w.wroteHeader
+}
+
+func (w *trackingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *trackingWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, xerrors.New("http.Hijacker is not supported")
+	}
+	w.wroteHeader = true
+	return h.Hijack()
+}
+
+// responseBuffer holds a whole response to be inspected before sent
+type responseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseBuffer() *responseBuffer {
+	return &responseBuffer{
+		header: http.Header{}}
+}
+
+func (b *responseBuffer) Header() http.Header {
+	return b.header
+}
+
+func (b *responseBuffer) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+func (b *responseBuffer) Write(p []byte) (int, error) {
+	b.WriteHeader(http.StatusOK)
+	return b.body.Write(p)
+}
+
+// flush sends the buffered response as-is
+func (b *responseBuffer) flush(w http.ResponseWriter) {
+	for k, vs := range b.header {
+		w.Header()[k] = vs
+	}
+	if b.status != 0 {
+		w.WriteHeader(b.status)
+	}
+	w.Write(b.body.Bytes())
+}
+
+// renderMarker is a writer to be notified of responses rendered by RenderHTTPErr
+type renderMarker interface {
+	markRendered()
+}
+
+// markRendered notifies writers wrapped by w, following Unwrap as http.ResponseController does
+func markRendered(w http.ResponseWriter) {
+	for {
+		if m, ok := w.(renderMarker); ok {
+			m.markRendered()
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return
+		}
+		w = u.Unwrap()
+	}
+}
+
+// headerSent reports whether headers were sent through writers wrapped by w
+func headerSent(w http.ResponseWriter) bool {
+	for {
+		if s, ok := w.(interface{ headerSent() bool }); ok && s.headerSent() {
+			return true
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return false
+		}
+		w = u.Unwrap()
+	}
+}
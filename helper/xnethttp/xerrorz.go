@@ -1,22 +1,100 @@
 package xnethttp
 
 import (
-	"encoding/json"
-	"fmt"
 	"net/http"
+	"strconv"
 
 	"github.com/amaya382/xerrorz"
+	"golang.org/x/xerrors"
 )
 
+// ErrHeadersSent is returned by RenderHTTPErr when it is too late to render
+var ErrHeadersSent = xerrors.New("headers were already sent")
+
+// fallbackBody is written when rendering fails, not depending on encoders nor renderer policies
+var fallbackBody = []byte(`{"error":{"errors":[],"code":500,"message":"Internal server error"}}`)
+
+// SetHTTPErrJSON and the other SetHTTPErr* without requests never correlate requests, i.e. echo X-Request-ID,
+// nor apply request-dependent policies such as DebugByHeader and CORS. Use SetHTTPErr*For for them.
 func SetHTTPErrJSON(w http.ResponseWriter, errType xerrorz.ErrType, innerErrs ...*xerrorz.InnerErr) {
-	httpErr := xerrorz.NewHTTPErr(errType, innerErrs...)
-	bJSON, err := json.Marshal(httpErr)
+	SetHTTPErrJSONFor(w, nil, errType, innerErrs...)
+}
+
+func SetHTTPErrXML(w http.ResponseWriter, errType xerrorz.ErrType, innerErrs ...*xerrorz.InnerErr) {
+	SetHTTPErrXMLFor(w, nil, errType, innerErrs...)
+}
+
+func SetHTTPErrText(w http.ResponseWriter, errType xerrorz.ErrType, innerErrs ...*xerrorz.InnerErr) {
+	SetHTTPErrTextFor(w, nil, errType, innerErrs...)
+}
+
+// SetHTTPErr is SetHTTPErrJSON with an arbitrary encoder such as xerrorz.AWSEncoder
+func SetHTTPErr(w http.ResponseWriter, enc xerrorz.Encoder, errType xerrorz.ErrType,
+	innerErrs ...*xerrorz.InnerErr) {
+	SetHTTPErrFor(w, nil, enc, errType, innerErrs...)
+}
+
+// SetHTTPErrJSONFor is SetHTTPErrJSON for the request, applying the policies of xerrorz.DefaultRenderer for it
+func SetHTTPErrJSONFor(w http.ResponseWriter, r *http.Request, errType xerrorz.ErrType,
+	innerErrs ...*xerrorz.InnerErr) {
+	RenderHTTPErr(w, r, xerrorz.JSONEncoder, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+func SetHTTPErrXMLFor(w http.ResponseWriter, r *http.Request, errType xerrorz.ErrType,
+	innerErrs ...*xerrorz.InnerErr) {
+	RenderHTTPErr(w, r, xerrorz.XMLEncoder, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+func SetHTTPErrTextFor(w http.ResponseWriter, r *http.Request, errType xerrorz.ErrType,
+	innerErrs ...*xerrorz.InnerErr) {
+	RenderHTTPErr(w, r, xerrorz.TextEncoder, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+func SetHTTPErrFor(w http.ResponseWriter, r *http.Request, enc xerrorz.Encoder, errType xerrorz.ErrType,
+	innerErrs ...*xerrorz.InnerErr) {
+	RenderHTTPErr(w, r, enc, xerrorz.NewHTTPErr(errType, innerErrs...))
+}
+
+// WriteHTTPErr writes an already built HTTPErr with the encoder, without requests as SetHTTPErrJSON
+func WriteHTTPErr(w http.ResponseWriter, enc xerrorz.Encoder, httpErr *xerrorz.HTTPErr) {
+	RenderHTTPErr(w, nil, enc, httpErr)
+}
+
+// RenderHTTPErr is WriteHTTPErr applying the policies of xerrorz.DefaultRenderer for the request.
+// All headers are set before the status, and the body is skipped for HEAD requests.
+// ErrHeadersSent is returned without writing if headers were already sent through writers of xnethttp.
+// If rendering fails, a pre-rendered InternalServerError json is written and the error is returned.
+func RenderHTTPErr(w http.ResponseWriter, r *http.Request, enc xerrorz.Encoder, httpErr *xerrorz.HTTPErr) error {
+	if headerSent(w) {
+		return ErrHeadersSent
+	}
+
+	res, err := xerrorz.DefaultRenderer.Render(r, enc, httpErr)
 	if err != nil {
-		panic("Failed to generate an error response")
+		res = &xerrorz.Response{
+			Status: http.StatusInternalServerError,
+			Header: http.Header{"Content-Type": {xerrorz.JSONEncoder.ContentType()}},
+			Body:   fallbackBody}
+		err = xerrors.Errorf("failed to render an error response: %w", err)
 	}
 
 	// Write
-	w.WriteHeader(httpErr.ErrDoc.Code)
-	w.Header().Set("Content-Type", "application/json")
-	fmt.Fprintf(w, string(bJSON))
+	markRendered(w)
+	h := w.Header()
+	for k, vs := range res.Header {
+		h[k] = vs
+	}
+	h.Set("Content-Type", xerrorz.WithCharset(h.Get("Content-Type")))
+	h.Set("Content-Length", strconv.Itoa(len(res.Body)))
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(res.Status)
+	if r == nil || r.Method != http.MethodHead {
+		w.Write(res.Body)
+	}
+	return err
+}
+
+// WriteJSONRPCErr writes a JSON-RPC response envelope for the request id set in enc
+func WriteJSONRPCErr(w http.ResponseWriter, enc xerrorz.JSONRPCEncoder, httpErr *xerrorz.HTTPErr) {
+	WriteHTTPErr(w, enc, httpErr)
 }
@@ -6,18 +6,25 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/amaya382/xerrorz"
 	"golang.org/x/xerrors"
 )
 
+func init() {
+	xerrorz.ErrIDGenerator = func() string { return "0123456789abcdef0123456789abcdef" }
+}
+
 const sampleJSON0 = `
 {
     "error": {
         "errors": [],
         "code": 400,
-        "message": "Invalid argument"
+        "message": "Invalid argument",
+        "id": "0123456789abcdef0123456789abcdef"
     }
 }
 `
@@ -35,7 +42,8 @@ const sampleJSON1 = `
             }
         ],
         "code": 400,
-        "message": "Invalid argument"
+        "message": "Invalid argument",
+        "id": "0123456789abcdef0123456789abcdef"
     }
 }
 `
@@ -60,7 +68,8 @@ const sampleJSON2 = `
             }
         ],
         "code": 400,
-        "message": "Invalid argument"
+        "message": "Invalid argument",
+        "id": "0123456789abcdef0123456789abcdef"
     }
 }
 `
@@ -76,7 +85,7 @@ func TestSetHTTPErrJSON0(t *testing.T) {
 		t.Fatalf("Invalid status code: %d\n", res.Code)
 	}
 
-	if res.HeaderMap.Get("Content-Type") != "application/json" {
+	if res.HeaderMap.Get("Content-Type") != "application/json; charset=utf-8" {
 		t.Fatalf("Invalid header: Content-Type:%s\n", res.HeaderMap.Get("Content-Type"))
 	}
 
@@ -112,7 +121,7 @@ func TestSetHTTPErrJSON1(t *testing.T) {
 		t.Fatal("Invalid status code")
 	}
 
-	if res.HeaderMap.Get("Content-Type") != "application/json" {
+	if res.HeaderMap.Get("Content-Type") != "application/json; charset=utf-8" {
 		t.Fatal("Invalid header")
 	}
 
@@ -153,7 +162,7 @@ func TestSetHTTPErrJSON2(t *testing.T) {
 		t.Fatal("Invalid status code")
 	}
 
-	if res.HeaderMap.Get("Content-Type") != "application/json" {
+	if res.HeaderMap.Get("Content-Type") != "application/json; charset=utf-8" {
 		t.Fatal("Invalid header")
 	}
 
@@ -171,3 +180,182 @@ func TestSetHTTPErrJSON2(t *testing.T) {
 		t.Fatalf("Inconsistent json was generated: %+v\n", err)
 	}
 }
+
+func TestSetHTTPErr0(t *testing.T) {
+	res := httptest.NewRecorder()
+
+	SetHTTPErr(res, xerrorz.AWSEncoder{}, xerrorz.NotFound,
+		xerrorz.NewInnerErr("s3", "noSuchKey", "foo/bar.txt", "path", "The specified key does not exist.", nil))
+
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+
+	if res.Result().Header.Get("Content-Type") != "application/xml; charset=utf-8" {
+		t.Fatalf("Invalid header: Content-Type:%s\n", res.Result().Header.Get("Content-Type"))
+	}
+
+	if !strings.Contains(res.Body.String(), "<Code>NoSuchKey</Code>") {
+		t.Fatalf("Invalid body: %s\n", res.Body.String())
+	}
+}
+
+func TestWriteJSONRPCErr0(t *testing.T) {
+	res := httptest.NewRecorder()
+
+	WriteJSONRPCErr(res, xerrorz.JSONRPCEncoder{ID: "req-1"}, xerrorz.NewHTTPErr(xerrorz.ParseError))
+
+	if res.Code != http.StatusOK {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+
+	var o struct {
+		JSONRPC string
+		Error   struct{ Code int }
+		ID      string
+	}
+	if err := json.Unmarshal(res.Body.Bytes(), &o); err != nil {
+		t.Fatalf("Failed to unmarshal a json: %+v\n", err)
+	}
+	if o.JSONRPC != "2.0" || o.Error.Code != xerrorz.JSONRPCParseError || o.ID != "req-1" {
+		t.Fatalf("Invalid envelope: %s\n", res.Body.String())
+	}
+}
+
+func TestSetHTTPErr1(t *testing.T) {
+	// Status decided by the encoder
+	res := httptest.NewRecorder()
+
+	SetHTTPErr(res, xerrorz.TwirpEncoder{}, xerrorz.MethodNotAllowed)
+
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+
+	if !strings.Contains(res.Body.String(), `"code":"bad_route"`) {
+		t.Fatalf("Invalid body: %s\n", res.Body.String())
+	}
+}
+
+func TestSetHTTPErrText0(t *testing.T) {
+	res := httptest.NewRecorder()
+
+	SetHTTPErrText(res, xerrorz.NotFound)
+
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+
+	if res.Result().Header.Get("Content-Type") != "text/plain; charset=utf-8" {
+		t.Fatalf("Invalid header: Content-Type:%s\n", res.Result().Header.Get("Content-Type"))
+	}
+
+	if res.Body.String() != "404 Not found\n" {
+		t.Fatalf("Invalid body: %s\n", res.Body.String())
+	}
+}
+
+func TestRenderHTTPErr0(t *testing.T) {
+	xerrorz.DefaultRenderer.Debug = xerrorz.DebugByHeader("X-Debug-Token", "secret")
+	defer func() { xerrorz.DefaultRenderer.Debug = nil }()
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-Debug-Token", "secret")
+	res := httptest.NewRecorder()
+
+	RenderHTTPErr(res, r, xerrorz.JSONEncoder, xerrorz.NewHTTPErr(xerrorz.InvalidArgument,
+		xerrorz.NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid",
+			io.ErrNoProgress)))
+
+	if !strings.Contains(res.Body.String(), `"debug":`) {
+		t.Fatalf("No debug object: %s\n", res.Body.String())
+	}
+}
+
+func TestRenderHTTPErr1(t *testing.T) {
+	// Headers
+	res := httptest.NewRecorder()
+	err := RenderHTTPErr(res, httptest.NewRequest("GET", "/", nil), xerrorz.JSONEncoder,
+		xerrorz.NewHTTPErr(xerrorz.NotFound))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+
+	h := res.Result().Header
+	if h.Get("Content-Type") != "application/json; charset=utf-8" || h.Get("X-Content-Type-Options") != "nosniff" ||
+		h.Get("Content-Length") != strconv.Itoa(res.Body.Len()) {
+		t.Fatalf("Invalid headers: %v\n", h)
+	}
+
+	// HEAD
+	res = httptest.NewRecorder()
+	RenderHTTPErr(res, httptest.NewRequest("HEAD", "/", nil), xerrorz.JSONEncoder, xerrorz.NewHTTPErr(xerrorz.NotFound))
+	if res.Code != http.StatusNotFound || res.Body.Len() != 0 || res.Result().Header.Get("Content-Length") != h.Get("Content-Length") {
+		t.Fatalf("Invalid response: %d, %s, %v\n", res.Code, res.Body.String(), res.Result().Header)
+	}
+}
+
+func TestRenderHTTPErr2(t *testing.T) {
+	// Headers already sent
+	res := httptest.NewRecorder()
+	tw := &trackingWriter{ResponseWriter: res}
+	tw.WriteHeader(http.StatusOK)
+	tw.Write([]byte("ok"))
+
+	err := RenderHTTPErr(tw, nil, xerrorz.JSONEncoder, xerrorz.NewHTTPErr(xerrorz.NotFound))
+	if !xerrors.Is(err, ErrHeadersSent) {
+		t.Fatalf("Invalid error: %+v\n", err)
+	}
+	if res.Code != http.StatusOK || res.Body.String() != "ok" {
+		t.Fatalf("Invalid response: %d, %s\n", res.Code, res.Body.String())
+	}
+}
+
+type failingEncoder struct{}
+
+func (failingEncoder) ContentType() string { return "application/json" }
+
+func (failingEncoder) Encode(w io.Writer, e *xerrorz.HTTPErr) error {
+	return io.ErrShortWrite
+}
+
+func TestRenderHTTPErr3(t *testing.T) {
+	// Fallback
+	res := httptest.NewRecorder()
+	err := RenderHTTPErr(res, nil, failingEncoder{}, xerrorz.NewHTTPErr(xerrorz.NotFound))
+	if !xerrors.Is(err, io.ErrShortWrite) {
+		t.Fatalf("Invalid error: %+v\n", err)
+	}
+	if res.Code != http.StatusInternalServerError {
+		t.Fatalf("Invalid status code: %d\n", res.Code)
+	}
+	httpErr, err := xerrorz.ParseHTTPErr(res.Body.Bytes())
+	if err != nil {
+		t.Fatalf("Failed to parse an err json: %+v\n", err)
+	}
+	if httpErr.Type != xerrorz.InternalServerError {
+		t.Fatalf("Invalid type: %s\n", httpErr.Type)
+	}
+}
+
+func TestRenderHTTPErr4(t *testing.T) {
+	// Status headers
+	res := httptest.NewRecorder()
+	RenderHTTPErr(res, nil, xerrorz.JSONEncoder,
+		xerrorz.NewHTTPErr(xerrorz.NotAuthenticated).WithChallenges(`Bearer realm="foo"`))
+	if res.Result().Header.Get("WWW-Authenticate") != `Bearer realm="foo"` {
+		t.Fatalf("Invalid headers: %v\n", res.Result().Header)
+	}
+}
+
+func TestSetHTTPErrJSONFor0(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(xerrorz.RequestIDHeader, "req-1")
+	res := httptest.NewRecorder()
+	SetHTTPErrJSONFor(res, r, xerrorz.NotFound)
+
+	if res.Result().Header.Get(xerrorz.RequestIDHeader) != "req-1" ||
+		!strings.Contains(res.Body.String(), `"requestId":"req-1"`) {
+		t.Fatalf("Request ID was not echoed: %v, %s\n", res.Result().Header, res.Body.String())
+	}
+}
@@ -0,0 +1,69 @@
+package xerrorz
+
+import (
+	"html/template"
+	"io"
+)
+
+// HTMLEncoder renders error pages for browsers with html/template, so every message is escaped.
+// Templates are executed with the *HTTPErr. Built-in pages per status class are used for unregistered ErrTypes.
+type HTMLEncoder struct {
+	Templates map[ErrType]*template.Template
+}
+
+// Register sets a custom template for an ErrType
+func (enc *HTMLEncoder) Register(errType ErrType, tmpl *template.Template) {
+	if enc.Templates == nil {
+		enc.Templates = map[ErrType]*template.Template{}
+	}
+	enc.Templates[errType] = tmpl
+}
+
+func (HTMLEncoder) ContentType() string {
+	return "text/html; charset=utf-8"
+}
+
+func (enc HTMLEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	return enc.template(e).Execute(w, e)
+}
+
+func (enc HTMLEncoder) template(e *HTTPErr) *template.Template {
+	if tmpl, ok := enc.Templates[e.errType()]; ok {
+		return tmpl
+	}
+	if e.ErrDoc.Code >= 500 {
+		return serverErrHTML
+	}
+	return clientErrHTML
+}
+
+const htmlHead = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.ErrDoc.Code}} {{.ErrDoc.Message}}</title>
+<style>
+body { font-family: sans-serif; margin: 4em auto; max-width: 40em; color: #333; }
+h1 { font-size: 1.5em; }
+li { margin: .5em 0; }
+.location { color: #888; }
+</style>
+</head>
+<body>
+<h1>{{.ErrDoc.Code}} {{.ErrDoc.Message}}</h1>
+`
+
+const htmlFoot = `</body>
+</html>
+`
+
+// Inner errors are listed only for client errors, never for server errors
+var clientErrHTML = template.Must(template.New("clientErr").Parse(htmlHead + `{{with .ErrDoc.Errors}}<ul>
+{{range .}}<li>{{.Message}}{{if .Location}} <span class="location">({{.Location}})</span>{{end}}</li>
+{{end}}</ul>
+{{end}}` + htmlFoot))
+
+var serverErrHTML = template.Must(template.New("serverErr").Parse(htmlHead +
+	`<p>Something went wrong on our side. Please try again later.</p>
+` + htmlFoot))
@@ -0,0 +1,63 @@
+package xerrorz
+
+import (
+	"bytes"
+	"html/template"
+	"strings"
+	"testing"
+)
+
+func TestHTMLEncoder0(t *testing.T) {
+	// Messages are escaped
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "<script>alert(1)</script>", nil))
+
+	var buf bytes.Buffer
+	if err := (HTMLEncoder{}).Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	page := buf.String()
+
+	if !strings.Contains(page, "<title>400 Invalid argument</title>") {
+		t.Fatalf("No title: %s\n", page)
+	}
+	if strings.Contains(page, "<script>") || !strings.Contains(page, "&lt;script&gt;alert(1)&lt;/script&gt;") {
+		t.Fatalf("Not escaped: %s\n", page)
+	}
+}
+
+func TestHTMLEncoder1(t *testing.T) {
+	// Inner errors of server errors are not shown
+	errRes := NewHTTPErr(InternalServerError,
+		NewInnerErr("fooService", "db", "", "", "connection refused", nil))
+
+	var buf bytes.Buffer
+	if err := (HTMLEncoder{}).Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	if strings.Contains(buf.String(), "connection refused") {
+		t.Fatalf("Inner error was shown: %s\n", buf.String())
+	}
+}
+
+func TestHTMLEncoder2(t *testing.T) {
+	// Custom template
+	enc := HTMLEncoder{}
+	enc.Register(NotFound, template.Must(template.New("notFound").Parse(`<p>No such page: {{.ErrDoc.Message}}</p>`)))
+
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, NewHTTPErr(NotFound)); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	if buf.String() != "<p>No such page: Not found</p>" {
+		t.Fatalf("Custom template was not used: %s\n", buf.String())
+	}
+
+	buf.Reset()
+	if err := enc.Encode(&buf, NewHTTPErr(Gone)); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	if !strings.Contains(buf.String(), "<h1>410 Resources or session has gone</h1>") {
+		t.Fatalf("Default template was not used: %s\n", buf.String())
+	}
+}
@@ -0,0 +1,168 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// Based on https://www.jsonrpc.org/specification#error_object
+const (
+	JSONRPCParseError     = -32700
+	JSONRPCInvalidRequest = -32600
+	JSONRPCMethodNotFound = -32601
+	JSONRPCInvalidParams  = -32602
+	JSONRPCInternalError  = -32603
+
+	// Default upper bound of the server error range, -32000 to -32099
+	JSONRPCServerErrorBase = -32000
+	jsonrpcServerErrorMin  = -32099
+)
+
+type JSONRPCResponse struct {
+	JSONRPC string      `json:"jsonrpc"` // Always "2.0"
+	Error   *JSONRPCErr `json:"error"`
+	ID      interface{} `json:"id"` // Same as the request, null if unknown
+}
+
+type JSONRPCErr struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    *JSONRPCErrData `json:"data,omitempty"`
+}
+
+type JSONRPCErrData struct {
+	Type   string      `json:"type"`   // ErrType name
+	Status int         `json:"status"` // http status
+	Errors []*InnerErr `json:"errors"`
+}
+
+var jsonrpcCodes = map[ErrType]int{
+	ParseError:          JSONRPCParseError,
+	InvalidAltVaule:     JSONRPCInvalidParams,
+	InvalidArgument:     JSONRPCInvalidParams,
+	InvalidParameter:    JSONRPCInvalidParams,
+	Required:            JSONRPCInvalidParams,
+	InternalServerError: JSONRPCInternalError,
+}
+
+// JSONRPCEncoder renders a JSON-RPC 2.0 response envelope with an error member.
+// ErrTypes without reserved codes are mapped into the server error range as ServerErrorBase - ErrType.
+type JSONRPCEncoder struct {
+	ID              interface{} // Request id
+	ServerErrorBase int         // JSONRPCServerErrorBase if 0, clamped for codes of all ErrTypes to be in the range
+}
+
+func (JSONRPCEncoder) ContentType() string {
+	return "application/json"
+}
+
+// Status is always 200 as JSON-RPC carries errors in the body
+func (JSONRPCEncoder) Status(e *HTTPErr) int {
+	return http.StatusOK
+}
+
+func (enc JSONRPCEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	bJSON, err := json.Marshal(JSONRPCResponse{
+		JSONRPC: "2.0",
+		Error:   enc.JSONRPCErr(e),
+		ID:      enc.ID})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bJSON)
+	return err
+}
+
+// JSONRPCErr maps an HTTPErr into a JSON-RPC error member
+func (enc JSONRPCEncoder) JSONRPCErr(e *HTTPErr) *JSONRPCErr {
+	return &JSONRPCErr{
+		Code:    enc.Code(e.errType()),
+		Message: e.ErrDoc.Message,
+		Data: &JSONRPCErrData{
+			Type:   e.errType().String(),
+			Status: e.ErrDoc.Code,
+			Errors: e.ErrDoc.Errors}}
+}
+
+func (enc JSONRPCEncoder) Code(errType ErrType) int {
+	if code, ok := jsonrpcCodes[errType]; ok {
+		return code
+	}
+	return enc.serverErrorBase() - int(errType)
+}
+
+// ErrType maps back a JSON-RPC error code, preferring the type carried in data
+func (enc JSONRPCEncoder) ErrType(rpcErr *JSONRPCErr) ErrType {
+	if rpcErr.Data != nil {
+		if errType, ok := ParseErrType(rpcErr.Data.Type); ok {
+			return errType
+		}
+	}
+
+	switch rpcErr.Code {
+	case JSONRPCParseError:
+		return ParseError
+	case JSONRPCInvalidParams:
+		return InvalidParameter
+	case JSONRPCInternalError:
+		return InternalServerError
+	case JSONRPCInvalidRequest, JSONRPCMethodNotFound:
+		return BadRequest
+	}
+	offset := enc.serverErrorBase() - rpcErr.Code
+	if _, ok := errs[ErrType(offset)]; offset >= 0 && ok {
+		return ErrType(offset)
+	}
+	return InternalServerError
+}
+
+// Decode decodes a JSON-RPC response envelope with an error member
+func (enc JSONRPCEncoder) Decode(b []byte) (*HTTPErr, error) {
+	var res struct {
+		Error *JSONRPCErr `json:"error"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	if res.Error == nil {
+		return nil, nil
+	}
+
+	errType := enc.ErrType(res.Error)
+	errDoc := HTTPErrDoc{
+		Errors:  []*InnerErr{},
+		Code:    errs[errType].Code,
+		Message: res.Error.Message}
+	if res.Error.Data != nil {
+		if res.Error.Data.Status != 0 {
+			errDoc.Code = res.Error.Data.Status
+		}
+		if res.Error.Data.Errors != nil {
+			errDoc.Errors = res.Error.Data.Errors
+		}
+	}
+	return &HTTPErr{
+		ErrDoc: errDoc,
+		Type:   errType}, nil
+}
+
+func (enc JSONRPCEncoder) serverErrorBase() int {
+	if enc.ServerErrorBase == 0 || enc.ServerErrorBase > JSONRPCServerErrorBase {
+		return JSONRPCServerErrorBase
+	}
+	if min := jsonrpcServerErrorMin + int(lastErrType()); enc.ServerErrorBase < min {
+		return min
+	}
+	return enc.ServerErrorBase
+}
+
+func lastErrType() ErrType {
+	var res ErrType
+	for errType := range errs {
+		if errType > res {
+			res = errType
+		}
+	}
+	return res
+}
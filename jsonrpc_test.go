@@ -0,0 +1,91 @@
+package xerrorz
+
+import (
+	"bytes"
+	"testing"
+)
+
+const sampleJSONRPC = `
+{
+	"jsonrpc": "2.0",
+	"error": {
+	  "code": -32602,
+	  "message": "Invalid argument",
+	  "data": {
+		"type": "InvalidArgument",
+		"status": 400,
+		"errors": [
+		  {
+			"domain": "fooService",
+			"reason": "invalidArgument",
+			"location": "id",
+			"locationType": "params",
+			"message": "Passed id is invalid"
+		  }
+		]
+	  }
+	},
+	"id": 1
+}`
+
+func TestJSONRPCEncoder0(t *testing.T) {
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "params", "Passed id is invalid", nil))
+
+	var buf bytes.Buffer
+	if err := (JSONRPCEncoder{ID: 1}).Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	assertJSONEqual(t, buf.Bytes(), sampleJSONRPC)
+}
+
+func TestJSONRPCEncoder1(t *testing.T) {
+	enc := JSONRPCEncoder{}
+	if code := enc.Code(ParseError); code != JSONRPCParseError {
+		t.Fatalf("Invalid code: %d\n", code)
+	}
+	if code := enc.Code(InternalServerError); code != JSONRPCInternalError {
+		t.Fatalf("Invalid code: %d\n", code)
+	}
+	if code := enc.Code(NotFound); code != JSONRPCServerErrorBase-int(NotFound) {
+		t.Fatalf("Invalid code: %d\n", code)
+	}
+
+	// Configured range
+	enc = JSONRPCEncoder{ServerErrorBase: -32050}
+	if code := enc.Code(NotFound); code != -32050-int(NotFound) {
+		t.Fatalf("Invalid code: %d\n", code)
+	}
+	if errType := enc.ErrType(&JSONRPCErr{Code: -32050 - int(NotFound)}); errType != NotFound {
+		t.Fatalf("Invalid type: %s\n", errType)
+	}
+
+	// Clamped into the server error range
+	for _, base := range []int{-32090, -31000} {
+		enc = JSONRPCEncoder{ServerErrorBase: base}
+		for errType := range errs {
+			if _, ok := jsonrpcCodes[errType]; ok {
+				continue
+			}
+			if code := enc.Code(errType); code < -32099 || code > -32000 {
+				t.Fatalf("Out of range: %s, %d\n", errType, code)
+			}
+			if decoded := enc.ErrType(&JSONRPCErr{Code: enc.Code(errType)}); decoded != errType {
+				t.Fatalf("Invalid type: %s, %s\n", errType, decoded)
+			}
+		}
+	}
+}
+
+func TestJSONRPCEncoder2(t *testing.T) {
+	errRes, err := (JSONRPCEncoder{}).Decode([]byte(sampleJSONRPC))
+	if err != nil {
+		t.Fatalf("Failed to decode an err json: %+v\n", err)
+	}
+	if errRes.Type != InvalidArgument || errRes.ErrDoc.Code != 400 {
+		t.Fatalf("Invalid type or status: %s, %d\n", errRes.Type, errRes.ErrDoc.Code)
+	}
+	if len(errRes.ErrDoc.Errors) != 1 || errRes.ErrDoc.Errors[0].Location != "id" {
+		t.Fatalf("Invalid inner errors: %+v\n", errRes.ErrDoc.Errors)
+	}
+}
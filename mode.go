@@ -0,0 +1,34 @@
+package xerrorz
+
+import (
+	"os"
+	"sync/atomic"
+
+	"golang.org/x/xerrors"
+)
+
+// Mode switches development-only features such as DebugHTMLEncoder
+type Mode int32
+
+const (
+	ProductionMode Mode = iota // Default
+	DevelopmentMode
+)
+
+// ModeEnv must be "development" to enable DevelopmentMode, so that deployed servers never enable it by accident
+const ModeEnv = "XERRORZ_MODE"
+
+var mode int32 = int32(ProductionMode)
+
+func CurrentMode() Mode {
+	return Mode(atomic.LoadInt32(&mode))
+}
+
+// SetMode switches the mode. DevelopmentMode is refused unless ModeEnv is "development".
+func SetMode(m Mode) error {
+	if m == DevelopmentMode && os.Getenv(ModeEnv) != "development" {
+		return xerrors.Errorf("development mode requires %s=development", ModeEnv)
+	}
+	atomic.StoreInt32(&mode, int32(m))
+	return nil
+}
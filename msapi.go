@@ -0,0 +1,105 @@
+package xerrorz
+
+import (
+	"encoding/json"
+)
+
+// Based on https://github.com/microsoft/api-guidelines/blob/vNext/Guidelines.md#7102-error-condition-responses
+type MSErr struct {
+	ErrDoc MSErrDoc `json:"error"`
+}
+
+type MSErrDoc struct {
+	Code       string      `json:"code"`                 // ErrType name for the top-level, InnerErr.Reason for details
+	Message    string      `json:"message"`              // {description}
+	Target     string      `json:"target,omitempty"`     // InnerErr.Location
+	Details    []MSErrDoc  `json:"details,omitempty"`    // InnerErrs
+	InnerError *MSInnerErr `json:"innererror,omitempty"` // Cause chain, only in debug mode
+}
+
+type MSInnerErr struct {
+	Code       string      `json:"code,omitempty"`
+	Message    string      `json:"message,omitempty"`
+	InnerError *MSInnerErr `json:"innererror,omitempty"`
+}
+
+// NewMSErr maps an HTTPErr into the Microsoft REST API guidelines structure.
+// Causes of InnerErrs are folded into nested innererror objects only if debug is true.
+func NewMSErr(e *HTTPErr, debug bool) *MSErr {
+	doc := MSErrDoc{
+		Code:    e.errType().String(),
+		Message: e.ErrDoc.Message}
+	for _, iErr := range e.ErrDoc.Errors {
+		detail := MSErrDoc{
+			Code:    iErr.Reason,
+			Message: iErr.Message,
+			Target:  iErr.Location}
+		if debug && iErr.Cause != nil {
+			detail.InnerError = newMSInnerErr(causeMessages(iErr.Cause))
+		}
+		doc.Details = append(doc.Details, detail)
+	}
+	return &MSErr{
+		ErrDoc: doc}
+}
+
+// HTTPErr maps back into an HTTPErr. status is used if the code is not an ErrType name.
+func (m *MSErr) HTTPErr(status int) *HTTPErr {
+	errType, ok := ParseErrType(m.ErrDoc.Code)
+	if !ok {
+		errType = ErrTypeForStatus(status)
+	}
+	if status == 0 {
+		status = errs[errType].Code
+	}
+
+	innerErrs := []*InnerErr{}
+	for _, detail := range m.ErrDoc.Details {
+		innerErrs = append(innerErrs, &InnerErr{
+			Reason:   detail.Code,
+			Location: detail.Target,
+			Message:  detail.Message,
+			Cause:    detail.InnerError.cause()})
+	}
+	return &HTTPErr{
+		ErrDoc: HTTPErrDoc{
+			Errors:  innerErrs,
+			Code:    status,
+			Message: m.ErrDoc.Message},
+		Type: errType}
+}
+
+func EncodeMSErr(e *HTTPErr, debug bool) ([]byte, error) {
+	return json.Marshal(NewMSErr(e, debug))
+}
+
+// DecodeMSErr decodes a Microsoft style error json responded with the status
+func DecodeMSErr(status int, b []byte) (*HTTPErr, error) {
+	var m MSErr
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, err
+	}
+	return m.HTTPErr(status), nil
+}
+
+func newMSInnerErr(msgs []string) *MSInnerErr {
+	if len(msgs) == 0 {
+		return nil
+	}
+	return &MSInnerErr{
+		Message:    msgs[0],
+		InnerError: newMSInnerErr(msgs[1:])}
+}
+
+func (ie *MSInnerErr) cause() error {
+	if ie == nil {
+		return nil
+	}
+	msg := ie.Message
+	if msg == "" {
+		msg = ie.Code
+	}
+	return remoteCause{
+		msg:   msg,
+		cause: ie.InnerError.cause()}
+}
@@ -0,0 +1,133 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+const sampleMSJSON = `
+{
+	"error": {
+	  "code": "InvalidArgument",
+	  "message": "Invalid argument",
+	  "details": [
+		{
+		  "code": "invalidArgument",
+		  "message": "Passed id is invalid",
+		  "target": "id"
+		},
+		{
+		  "code": "invalidArgument",
+		  "message": "Passed name is invalid",
+		  "target": "name"
+		}
+	  ]
+	}
+}`
+
+const sampleMSDebugJSON = `
+{
+	"error": {
+	  "code": "InvalidArgument",
+	  "message": "Invalid argument",
+	  "details": [
+		{
+		  "code": "invalidArgument",
+		  "message": "Passed id is invalid",
+		  "target": "id",
+		  "innererror": {
+			"message": "e2",
+			"innererror": {
+			  "message": "e1",
+			  "innererror": {
+				"message": "io: read/write on closed pipe"
+			  }
+			}
+		  }
+		}
+	  ]
+	}
+}`
+
+func TestMSErr0(t *testing.T) {
+	e1 := xerrors.Errorf("e1: %w", io.ErrClosedPipe)
+	e2 := xerrors.Errorf("e2: %w", e1)
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", e2),
+		NewInnerErr("fooService", "invalidArgument", "name", "requestBody", "Passed name is invalid", nil))
+	bJSON, err := EncodeMSErr(errRes, false)
+	if err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	assertJSONEqual(t, bJSON, sampleMSJSON)
+}
+
+func TestMSErr1(t *testing.T) {
+	// Debug mode
+	e1 := xerrors.Errorf("e1: %w", io.ErrClosedPipe)
+	e2 := xerrors.Errorf("e2: %w", e1)
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", e2))
+	bJSON, err := EncodeMSErr(errRes, true)
+	if err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	assertJSONEqual(t, bJSON, sampleMSDebugJSON)
+}
+
+func TestMSErr2(t *testing.T) {
+	errRes, err := DecodeMSErr(400, []byte(sampleMSDebugJSON))
+	if err != nil {
+		t.Fatalf("Failed to decode an err json: %+v\n", err)
+	}
+
+	if errRes.Type != InvalidArgument || errRes.ErrDoc.Code != 400 {
+		t.Fatalf("Invalid type or status: %s, %d\n", errRes.Type, errRes.ErrDoc.Code)
+	}
+	if len(errRes.ErrDoc.Errors) != 1 || errRes.ErrDoc.Errors[0].Location != "id" {
+		t.Fatalf("Invalid inner errors: %+v\n", errRes.ErrDoc.Errors)
+	}
+
+	msgs := causeMessages(errRes.ErrDoc.Errors[0].Cause)
+	if !reflect.DeepEqual(msgs, []string{"e2", "e1", "io: read/write on closed pipe"}) {
+		t.Fatalf("Invalid cause chain: %v\n", msgs)
+	}
+}
+
+func TestMSErr3(t *testing.T) {
+	// Unknown code falls back to the status
+	errRes, err := DecodeMSErr(503, []byte(`{"error":{"code":"Overloaded","message":"Try later"}}`))
+	if err != nil {
+		t.Fatalf("Failed to decode an err json: %+v\n", err)
+	}
+	if errRes.Type != ServiceUnavailable || errRes.ErrDoc.Code != 503 {
+		t.Fatalf("Invalid type or status: %s, %d\n", errRes.Type, errRes.ErrDoc.Code)
+	}
+}
+
+func assertJSONEqual(t *testing.T, bJSON []byte, sample string) {
+	t.Helper()
+
+	var o1, o2 interface{}
+	if err := json.Unmarshal(bJSON, &o1); err != nil {
+		t.Fatalf("Failed to unmarshal an err json: %+v\n", err)
+	}
+	if err := json.Unmarshal([]byte(sample), &o2); err != nil {
+		t.Fatalf("Failed to unmarshal a sample json: %+v\n", err)
+	}
+	if !reflect.DeepEqual(o1, o2) {
+		t.Fatalf("Inconsistent json was generated: %s\n", bJSON)
+	}
+}
+
+func TestMSErr4(t *testing.T) {
+	// Type is resolved by the status of a literal
+	m := NewMSErr(&HTTPErr{ErrDoc: HTTPErrDoc{Code: 503, Errors: []*InnerErr{}}}, false)
+	if m.ErrDoc.Code != "ServiceUnavailable" {
+		t.Fatalf("Invalid code: %s\n", m.ErrDoc.Code)
+	}
+}
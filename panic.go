@@ -0,0 +1,54 @@
+package xerrorz
+
+import (
+	"fmt"
+	"runtime/debug"
+
+	"golang.org/x/xerrors"
+)
+
+// PanicErr is a recovered panic value with the goroutine stack
+type PanicErr struct {
+	Value interface{}
+	Stack []byte
+}
+
+// NewPanicErr captures the current goroutine stack, call it in the deferred function recovering v
+func NewPanicErr(v interface{}) *PanicErr {
+	return &PanicErr{
+		Value: v,
+		Stack: debug.Stack()}
+}
+
+func (e PanicErr) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+func (e PanicErr) Format(s fmt.State, v rune) {
+	xerrors.FormatError(e, s, v)
+}
+
+func (e PanicErr) FormatError(p xerrors.Printer) error {
+	p.Print(e.Error())
+	if p.Detail() {
+		p.Printf("%s", e.Stack)
+	}
+	return e.Unwrap()
+}
+
+// Unwrap returns the panic value if an error
+func (e PanicErr) Unwrap() error {
+	if err, ok := e.Value.(error); ok {
+		return err
+	}
+	return nil
+}
+
+// NewPanicHTTPErr builds an InternalServerError caused by a recovered panic
+func NewPanicHTTPErr(v interface{}) *HTTPErr {
+	res := NewHTTPErr(InternalServerError,
+		NewInnerErr("global", InternalServerError.reason(), "", "", errs[InternalServerError].Message,
+			NewPanicErr(v)))
+	res.frame = xerrors.Caller(1)
+	return res
+}
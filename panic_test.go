@@ -0,0 +1,67 @@
+package xerrorz
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestPanicErr0(t *testing.T) {
+	var errRes *HTTPErr
+	func() {
+		defer func() {
+			errRes = NewPanicHTTPErr(recover())
+		}()
+		panic("boom")
+	}()
+
+	if errRes.Type != InternalServerError {
+		t.Fatalf("Invalid type: %s\n", errRes.Type)
+	}
+
+	var pErr *PanicErr
+	if !xerrors.As(errRes.ErrDoc.Errors[0].Cause, &pErr) || pErr.Value != "boom" {
+		t.Fatalf("Invalid cause: %+v\n", errRes.ErrDoc.Errors[0].Cause)
+	}
+
+	errStr := fmt.Sprintf("%+v", errRes)
+	if !strings.Contains(errStr, "panic: boom") || !strings.Contains(errStr, "TestPanicErr0") {
+		t.Fatalf("No stack: %s\n", errStr)
+	}
+}
+
+func TestPanicErr1(t *testing.T) {
+	// Panic with an error
+	pErr := NewPanicErr(io.ErrClosedPipe)
+	if !xerrors.Is(pErr, io.ErrClosedPipe) {
+		t.Fatal("Panic value was not unwrapped")
+	}
+	if pErr.Error() != "panic: io: read/write on closed pipe" {
+		t.Fatalf("Invalid message: %s\n", pErr.Error())
+	}
+}
+
+func TestPanicErr2(t *testing.T) {
+	// The stack is kept apart from frames in debug information
+	var errRes *HTTPErr
+	func() {
+		defer func() {
+			errRes = NewPanicHTTPErr(recover())
+		}()
+		panic(io.ErrClosedPipe)
+	}()
+
+	causes := NewDebugDoc(errRes).Errors[0].Causes
+	if len(causes) != 2 || causes[0].Message != "panic: io: read/write on closed pipe" {
+		t.Fatalf("Invalid causes: %+v\n", causes)
+	}
+	if causes[0].Frame != nil || !strings.Contains(causes[0].Stack, "TestPanicErr2") {
+		t.Fatalf("Invalid stack: %+v\n", causes[0])
+	}
+	if causes[1].Message != "io: read/write on closed pipe" {
+		t.Fatalf("Invalid cause: %+v\n", causes[1])
+	}
+}
@@ -0,0 +1,89 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Based on https://tools.ietf.org/html/rfc7807
+type Problem struct {
+	Type       string      `json:"type"`                 // {typeBase}{ErrType} or about:blank
+	Title      string      `json:"title"`                // Reason phrase of the status
+	Status     int         `json:"status"`               // {code}
+	Detail     string      `json:"detail,omitempty"`     // {description}
+	Instance   string      `json:"instance,omitempty"`   // Not set by ProblemEncoder
+	ID         string      `json:"id,omitempty"`         // Extension member of {id}
+	RequestID  string      `json:"requestId,omitempty"`  // Extension member of {requestId}
+	Errors     []*InnerErr `json:"errors,omitempty"`     // Extension member of {errors}
+	Details    Details     `json:"details,omitempty"`    // Extension member of {details}
+	Debug      *DebugDoc   `json:"debug,omitempty"`      // Extension member of {debug}, only if a DebugPolicy allows
+	DebugToken string      `json:"debugToken,omitempty"` // Extension member of {debugToken}
+}
+
+// ProblemEncoder renders RFC 7807 problem details json. Inner errors, details and debug information are kept as extension members.
+type ProblemEncoder struct {
+	TypeBase string // `type` is TypeBase followed by the ErrType name, about:blank if empty
+}
+
+func (ProblemEncoder) ContentType() string {
+	return "application/problem+json"
+}
+
+func (enc ProblemEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	bJSON, err := json.Marshal(enc.Problem(e))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bJSON)
+	return err
+}
+
+// Problem maps an HTTPErr into problem details
+func (enc ProblemEncoder) Problem(e *HTTPErr) *Problem {
+	res := &Problem{
+		Type:       "about:blank",
+		Title:      http.StatusText(e.ErrDoc.Code),
+		Status:     e.ErrDoc.Code,
+		Detail:     e.ErrDoc.Message,
+		ID:         e.ErrDoc.ID,
+		RequestID:  e.ErrDoc.RequestID,
+		Errors:     e.ErrDoc.Errors,
+		Details:    e.ErrDoc.Details,
+		Debug:      e.ErrDoc.Debug,
+		DebugToken: e.ErrDoc.DebugToken}
+	if enc.TypeBase != "" {
+		res.Type = enc.TypeBase + e.errType().String()
+	}
+	return res
+}
+
+// Decode decodes a problem details json
+func (enc ProblemEncoder) Decode(b []byte) (*HTTPErr, error) {
+	var p Problem
+	if err := json.Unmarshal(b, &p); err != nil {
+		return nil, err
+	}
+
+	errType := ErrTypeForStatus(p.Status)
+	if enc.TypeBase != "" && strings.HasPrefix(p.Type, enc.TypeBase) {
+		if t, ok := ParseErrType(strings.TrimPrefix(p.Type, enc.TypeBase)); ok {
+			errType = t
+		}
+	}
+	if p.Errors == nil {
+		p.Errors = []*InnerErr{}
+	}
+	return &HTTPErr{
+		ErrDoc: HTTPErrDoc{
+			Errors:     p.Errors,
+			Code:       p.Status,
+			Message:    p.Detail,
+			Details:    p.Details,
+			ID:         p.ID,
+			RequestID:  p.RequestID,
+			Debug:      p.Debug,
+			DebugToken: p.DebugToken},
+		Type: errType}, nil
+}
@@ -0,0 +1,104 @@
+package xerrorz
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+const sampleProblemJSON = `
+{
+	"type": "https://example.com/problems/InvalidArgument",
+	"title": "Bad Request",
+	"status": 400,
+	"detail": "Invalid argument",
+	"id": "0123456789abcdef0123456789abcdef",
+	"errors": [
+		{
+			"domain": "fooService",
+			"reason": "invalidArgument",
+			"location": "id",
+			"locationType": "requestBody",
+			"message": "Passed id is invalid"
+		}
+	]
+}`
+
+func TestProblem0(t *testing.T) {
+	enc := ProblemEncoder{TypeBase: "https://example.com/problems/"}
+	var buf bytes.Buffer
+	err := enc.Encode(&buf, NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", nil)))
+	if err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+
+	var o1, o2 interface{}
+	err = json.Unmarshal(buf.Bytes(), &o1)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal an err json: %+v\n", err)
+	}
+	err = json.Unmarshal([]byte(sampleProblemJSON), &o2)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal a sample json: %+v\n", err)
+	}
+
+	if !reflect.DeepEqual(o1, o2) {
+		t.Fatalf("Inconsistent json was generated: %s\n", buf.Bytes())
+	}
+}
+
+func TestProblem1(t *testing.T) {
+	// Parse back
+	httpErr, err := ProblemEncoder{TypeBase: "https://example.com/problems/"}.Decode([]byte(sampleProblemJSON))
+	if err != nil {
+		t.Fatalf("Failed to decode a problem json: %+v\n", err)
+	}
+	if httpErr.Type != InvalidArgument || httpErr.ErrDoc.Code != 400 || httpErr.ErrDoc.ID != sampleID {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+	if len(httpErr.ErrDoc.Errors) != 1 || httpErr.ErrDoc.Errors[0].Location != "id" {
+		t.Fatalf("Invalid inner errors: %+v\n", httpErr.ErrDoc.Errors)
+	}
+}
+
+func TestProblem2(t *testing.T) {
+	// about:blank
+	p := ProblemEncoder{}.Problem(NewHTTPErr(NotFound))
+	if p.Type != "about:blank" || p.Title != "Not Found" || p.Status != 404 {
+		t.Fatalf("Invalid problem: %+v\n", p)
+	}
+
+	httpErr, err := ProblemEncoder{}.Decode([]byte(`{"type":"about:blank","title":"Not Found","status":404}`))
+	if err != nil {
+		t.Fatalf("Failed to decode a problem json: %+v\n", err)
+	}
+	if httpErr.Type != NotFound {
+		t.Fatalf("Invalid type: %s\n", httpErr.Type)
+	}
+}
+
+func TestProblem3(t *testing.T) {
+	// Debug information
+	rd := &Renderer{Debug: func(r *http.Request) bool { return true }, DebugTokenKey: sampleKey}
+	res, err := rd.Render(nil, ProblemEncoder{}, NewHTTPErr(InternalServerError,
+		NewInnerErr("fooService", "db", "", "", "Failed to query", xerrors.New("pq: connection refused"))))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+
+	httpErr, err := ProblemEncoder{}.Decode(res.Body)
+	if err != nil {
+		t.Fatalf("Failed to decode a problem json: %+v\n", err)
+	}
+	if httpErr.ErrDoc.Debug == nil || len(httpErr.ErrDoc.Debug.Errors[0].Causes) == 0 {
+		t.Fatalf("No debug information: %s\n", res.Body)
+	}
+	if _, err := DecodeDebugToken(sampleKey, httpErr.ErrDoc.DebugToken); err != nil {
+		t.Fatalf("Failed to decode a debug token: %+v\n", err)
+	}
+}
@@ -0,0 +1,153 @@
+package xerrorz
+
+import (
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/xerrors"
+)
+
+// RateLimit is a token bucket holding up to Burst tokens refilled at Rate tokens per second
+type RateLimit struct {
+	Rate  float64 // Must be positive, buckets never refilled have no Retry-After
+	Burst int
+}
+
+// RateLimitResult is a state of a bucket after taking a token
+type RateLimitResult struct {
+	Allowed    bool
+	Remaining  int           // Tokens left
+	Reset      time.Duration // Until the bucket is full
+	RetryAfter time.Duration // Until the next token if not allowed
+}
+
+// RateLimitStore takes tokens from buckets by keys. Implement it for stores shared by processes such as Redis.
+type RateLimitStore interface {
+	Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error)
+}
+
+// RateLimiter takes a token per request, globally or per key such as a user ID
+type RateLimiter struct {
+	Limit RateLimit
+	Store RateLimitStore
+	Key   func(r *http.Request) string // Global if nil. Requests with an empty key are limited globally.
+}
+
+// NewRateLimiter limits requests globally with an in-memory store. rate must be positive.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if rate <= 0 {
+		panic("Rate of RateLimiter must be positive")
+	}
+	return &RateLimiter{
+		Limit: RateLimit{Rate: rate, Burst: burst},
+		Store: NewMemoryRateLimitStore()}
+}
+
+// Take takes a token for the request, returning RateLimit-* headers and an HTTPErr if exhausted.
+// The HTTPErr is UserRateLimitExceeded with a non-empty key, RateLimitExceeded otherwise,
+// with Retry-After and QuotaFailure. An error is returned without taking tokens if Rate is not positive.
+func (rl *RateLimiter) Take(r *http.Request) (http.Header, *HTTPErr, error) {
+	if rl.Limit.Rate <= 0 {
+		return nil, nil, xerrors.Errorf("rate must be positive: %g", rl.Limit.Rate)
+	}
+	key, errType, subject := "", RateLimitExceeded, "global"
+	if rl.Key != nil {
+		key = rl.Key(r)
+	}
+	if key != "" {
+		errType, subject = UserRateLimitExceeded, "user:"+key
+	}
+	res, err := rl.Store.Take(key, rl.Limit, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	h := http.Header{}
+	h.Set("RateLimit-Limit", strconv.Itoa(rl.Limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+	h.Set("RateLimit-Reset", strconv.FormatInt(ceilSeconds(res.Reset), 10))
+	if res.Allowed {
+		return h, nil, nil
+	}
+
+	description := fmt.Sprintf("Quota of %d requests refilled at %g requests per second was exhausted",
+		rl.Limit.Burst, rl.Limit.Rate)
+	httpErr := NewHTTPErr(errType,
+		NewInnerErr("global", errType.reason(), "", "", description, nil)).
+		WithRetryAfter(res.RetryAfter).
+		WithDetails(NewQuotaFailure(QuotaViolation{Subject: subject, Description: description}))
+	return h, httpErr, nil
+}
+
+// MemoryRateLimitStore keeps buckets in the process, dropping full ones from time to time
+type MemoryRateLimitStore struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	takes   int
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// memorySweepInterval is the number of takes between sweeps of full buckets
+const memorySweepInterval = 1024
+
+func NewMemoryRateLimitStore() *MemoryRateLimitStore {
+	return &MemoryRateLimitStore{
+		buckets: map[string]*tokenBucket{}}
+}
+
+func (s *MemoryRateLimitStore) Take(key string, limit RateLimit, now time.Time) (RateLimitResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.takes++
+	if s.takes%memorySweepInterval == 0 {
+		for k, b := range s.buckets {
+			if b.refill(limit, now) >= float64(limit.Burst) {
+				delete(s.buckets, k)
+			}
+		}
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &tokenBucket{tokens: float64(limit.Burst), last: now}
+		s.buckets[key] = b
+	}
+	b.tokens = b.refill(limit, now)
+	b.last = now
+
+	res := RateLimitResult{}
+	if b.tokens >= 1 {
+		b.tokens--
+		res.Allowed = true
+	} else if limit.Rate > 0 {
+		res.RetryAfter = rateDuration(1-b.tokens, limit.Rate)
+	}
+	res.Remaining = int(math.Floor(b.tokens))
+	if limit.Rate > 0 {
+		res.Reset = rateDuration(float64(limit.Burst)-b.tokens, limit.Rate)
+	}
+	return res, nil
+}
+
+// refill returns tokens of the bucket at now
+func (b *tokenBucket) refill(limit RateLimit, now time.Time) float64 {
+	tokens := b.tokens + now.Sub(b.last).Seconds()*limit.Rate
+	return math.Min(tokens, float64(limit.Burst))
+}
+
+func rateDuration(tokens float64, rate float64) time.Duration {
+	return time.Duration(tokens / rate * float64(time.Second))
+}
+
+func ceilSeconds(d time.Duration) int64 {
+	return int64((d + time.Second - 1) / time.Second)
+}
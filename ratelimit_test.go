@@ -0,0 +1,117 @@
+package xerrorz
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestMemoryRateLimitStore0(t *testing.T) {
+	s := NewMemoryRateLimitStore()
+	limit := RateLimit{Rate: 2, Burst: 2}
+	now := time.Unix(0, 0)
+
+	for i := 0; i < 2; i++ {
+		res, err := s.Take("foo", limit, now)
+		if err != nil {
+			t.Fatalf("Failed to take: %+v\n", err)
+		}
+		if !res.Allowed || res.Remaining != 1-i {
+			t.Fatalf("Invalid result: %+v\n", res)
+		}
+	}
+
+	res, err := s.Take("foo", limit, now)
+	if err != nil {
+		t.Fatalf("Failed to take: %+v\n", err)
+	}
+	if res.Allowed || res.RetryAfter != 500*time.Millisecond || res.Reset != time.Second {
+		t.Fatalf("Invalid result: %+v\n", res)
+	}
+
+	// Other keys and refills
+	if res, _ := s.Take("bar", limit, now); !res.Allowed {
+		t.Fatalf("Invalid result: %+v\n", res)
+	}
+	if res, _ := s.Take("foo", limit, now.Add(500*time.Millisecond)); !res.Allowed || res.Remaining != 0 {
+		t.Fatalf("Invalid result: %+v\n", res)
+	}
+}
+
+func TestRateLimiter0(t *testing.T) {
+	rl := NewRateLimiter(1, 1)
+	r := httptest.NewRequest("GET", "/", nil)
+
+	h, httpErr, err := rl.Take(r)
+	if err != nil || httpErr != nil {
+		t.Fatalf("Not allowed: %+v, %+v\n", httpErr, err)
+	}
+	if h.Get("RateLimit-Limit") != "1" || h.Get("RateLimit-Remaining") != "0" || h.Get("RateLimit-Reset") != "1" {
+		t.Fatalf("Invalid headers: %v\n", h)
+	}
+
+	_, httpErr, err = rl.Take(r)
+	if err != nil || httpErr == nil {
+		t.Fatalf("Allowed: %+v\n", err)
+	}
+	if httpErr.Type != RateLimitExceeded || httpErr.Headers.RetryAfter <= 0 {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+	qf, ok := httpErr.ErrDoc.Details[0].(*QuotaFailure)
+	if !ok || qf.Violations[0].Subject != "global" {
+		t.Fatalf("Invalid details: %#v\n", httpErr.ErrDoc.Details)
+	}
+}
+
+func TestRateLimiter1(t *testing.T) {
+	// Per user
+	rl := NewRateLimiter(1, 1)
+	rl.Key = func(r *http.Request) string { return r.Header.Get("X-User") }
+
+	for _, user := range []string{"foo", "bar"} {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.Header.Set("X-User", user)
+		if _, httpErr, _ := rl.Take(r); httpErr != nil {
+			t.Fatalf("Not allowed: %+v\n", httpErr)
+		}
+	}
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("X-User", "foo")
+	_, httpErr, _ := rl.Take(r)
+	if httpErr == nil || httpErr.Type != UserRateLimitExceeded || httpErr.ErrDoc.Errors[0].Reason != "userRateLimitExceeded" {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+	if qf := httpErr.ErrDoc.Details[0].(*QuotaFailure); qf.Violations[0].Subject != "user:foo" {
+		t.Fatalf("Invalid subject: %s\n", qf.Violations[0].Subject)
+	}
+}
+
+func TestRateLimiter2(t *testing.T) {
+	// Empty keys are limited globally
+	rl := NewRateLimiter(1, 1)
+	rl.Key = func(r *http.Request) string { return r.Header.Get("X-User") }
+
+	r := httptest.NewRequest("GET", "/", nil)
+	rl.Take(r)
+	_, httpErr, _ := rl.Take(r)
+	if httpErr == nil || httpErr.Type != RateLimitExceeded {
+		t.Fatalf("Invalid error: %+v\n", httpErr)
+	}
+	if qf := httpErr.ErrDoc.Details[0].(*QuotaFailure); qf.Violations[0].Subject != "global" {
+		t.Fatalf("Invalid subject: %s\n", qf.Violations[0].Subject)
+	}
+
+	// Buckets never refilled
+	rl = &RateLimiter{Limit: RateLimit{Rate: 0, Burst: 1}, Store: NewMemoryRateLimitStore()}
+	if _, _, err := rl.Take(r); err == nil {
+		t.Fatal("Zero rate was accepted")
+	}
+	defer func() {
+		if recover() == nil {
+			t.Fatal("Zero rate was accepted")
+		}
+	}()
+	NewRateLimiter(0, 1)
+}
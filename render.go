@@ -0,0 +1,123 @@
+package xerrorz
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+)
+
+// Renderer applies policies to HTTPErrs before they are encoded for a request
+type Renderer struct {
+	Debug    DebugPolicy                 // Never if nil
+	Sanitize *SanitizePolicy             // Disabled if nil
+	Log      func(id string, e *HTTPErr) // Called with the original of every rendered HTTPErr
+	Headers  *HeaderPolicy               // Disabled if nil
+
+	// Adds an encrypted debugToken with causes and frames for customer support if set, see EncodeDebugToken
+	DebugTokenKey []byte
+}
+
+// Response is a rendered HTTPErr to be written by the helpers
+type Response struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+const (
+	RequestIDHeader   = "X-Request-ID"
+	TraceParentHeader = "traceparent"
+)
+
+// DefaultRenderer is used by the helpers, only with Cache-Control: no-store. Configure it before serving requests.
+var DefaultRenderer = &Renderer{
+	Headers: &HeaderPolicy{}}
+
+// Prepare returns a copy of e to be rendered for the request. r may be nil.
+func (rd *Renderer) Prepare(r *http.Request, e *HTTPErr) *HTTPErr {
+	res := *e
+	if res.ErrDoc.RequestID == "" {
+		res.ErrDoc.RequestID = RequestID(r)
+	}
+	if rd.Sanitize != nil && rd.Sanitize.applies(e) {
+		if res.ErrDoc.ID == "" { // Sanitized errors always need an ID to find logs
+			if ErrIDGenerator != nil {
+				res.ErrDoc.ID = ErrIDGenerator()
+			} else {
+				res.ErrDoc.ID = RandomErrID()
+			}
+		}
+		res.ErrDoc = rd.Sanitize.sanitize(res.ErrDoc)
+	}
+	if rd.Debug != nil && rd.Debug(r) {
+		res.ErrDoc.Debug = NewDebugDoc(e)
+	}
+	if rd.DebugTokenKey != nil {
+		// A misconfigured key must not break error responses
+		orig := *e
+		orig.ErrDoc.ID, orig.ErrDoc.RequestID = res.ErrDoc.ID, res.ErrDoc.RequestID
+		if token, err := EncodeDebugToken(rd.DebugTokenKey, &orig); err == nil {
+			res.ErrDoc.DebugToken = token
+		}
+	}
+	if rd.Log != nil {
+		rd.Log(res.ErrDoc.ID, e)
+	}
+	return &res
+}
+
+// Render prepares and encodes e for the request, with headers of e.Headers. r may be nil.
+func (rd *Renderer) Render(r *http.Request, enc Encoder, e *HTTPErr) (*Response, error) {
+	prepared := rd.Prepare(r, e)
+
+	var buf bytes.Buffer
+	if err := enc.Encode(&buf, prepared); err != nil {
+		return nil, err
+	}
+
+	res := &Response{
+		Status: prepared.ErrDoc.Code,
+		Header: prepared.Headers.Header(),
+		Body:   buf.Bytes()}
+	if sEnc, ok := enc.(StatusEncoder); ok {
+		res.Status = sEnc.Status(prepared)
+	}
+	res.Header.Set("Content-Type", enc.ContentType())
+	if prepared.ErrDoc.RequestID != "" {
+		res.Header.Set(RequestIDHeader, prepared.ErrDoc.RequestID)
+	}
+	if rd.Headers != nil {
+		rd.Headers.apply(r, prepared, res.Header)
+	}
+	return res, nil
+}
+
+// RequestID picks up X-Request-ID, or the trace-id of W3C traceparent, of the request. r may be nil.
+func RequestID(r *http.Request) string {
+	if r == nil {
+		return ""
+	}
+	if id := r.Header.Get(RequestIDHeader); validRequestID(id) {
+		return id
+	}
+
+	// {version}-{trace-id}-{parent-id}-{trace-flags}
+	fields := strings.Split(r.Header.Get(TraceParentHeader), "-")
+	if len(fields) == 4 && len(fields[1]) == 32 && validRequestID(fields[1]) {
+		return fields[1]
+	}
+	return ""
+}
+
+// validRequestID accepts ids safe to be echoed back in headers and bodies
+func validRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for _, c := range id {
+		if c <= ' ' || c > '~' {
+			return false
+		}
+	}
+	return true
+}
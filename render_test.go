@@ -0,0 +1,65 @@
+package xerrorz
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestRequestID0(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	if id := RequestID(r); id != "" {
+		t.Fatalf("Invalid request ID: %s\n", id)
+	}
+
+	r.Header.Set(TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	if id := RequestID(r); id != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("Invalid request ID: %s\n", id)
+	}
+
+	// X-Request-ID is preferred
+	r.Header.Set(RequestIDHeader, "req-1")
+	if id := RequestID(r); id != "req-1" {
+		t.Fatalf("Invalid request ID: %s\n", id)
+	}
+
+	// Unsafe ids are ignored
+	r.Header.Set(RequestIDHeader, "req 1\x00")
+	r.Header.Del(TraceParentHeader)
+	if id := RequestID(r); id != "" {
+		t.Fatalf("Invalid request ID: %s\n", id)
+	}
+	if id := RequestID(nil); id != "" {
+		t.Fatalf("Invalid request ID: %s\n", id)
+	}
+}
+
+func TestRender0(t *testing.T) {
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set(RequestIDHeader, "req-1")
+
+	res, err := (&Renderer{}).Render(r, JSONEncoder, NewHTTPErr(NotFound))
+	if err != nil {
+		t.Fatalf("Failed to render: %+v\n", err)
+	}
+	if res.Status != 404 || res.Header.Get("Content-Type") != "application/json" ||
+		res.Header.Get(RequestIDHeader) != "req-1" {
+		t.Fatalf("Invalid response: %d, %v\n", res.Status, res.Header)
+	}
+	expected := `{"error":{"errors":[],"code":404,"message":"Not found","id":"` + sampleID + `","requestId":"req-1"}}`
+	if string(res.Body) != expected {
+		t.Fatalf("Inconsistent json was generated: %s\n", res.Body)
+	}
+}
+
+func TestErrID0(t *testing.T) {
+	errStr := fmt.Sprintf("%+v", NewHTTPErr(NotFound))
+	if !strings.HasPrefix(errStr, "[HTTP Status 404] Not found (ID: "+sampleID+")") {
+		t.Fatalf("No ID: %s\n", errStr)
+	}
+
+	if id := RandomErrID(); len(id) != 32 || id == RandomErrID() {
+		t.Fatalf("Invalid random ID: %s\n", id)
+	}
+}
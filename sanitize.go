@@ -0,0 +1,62 @@
+package xerrorz
+
+import (
+	"fmt"
+)
+
+// ServerErrTypes are masked by SanitizePolicy by default
+var ServerErrTypes = []ErrType{InternalServerError, BadGateway, ServiceUnavailable}
+
+// DefaultSanitizedMessage is followed by an error ID
+const DefaultSanitizedMessage = "An internal error occurred. Please contact support with the error ID"
+
+// SanitizePolicy replaces messages of HTTPErrs which may leak internals, such as database errors,
+// with a generic message and an error ID. The original is still passed to Renderer.Log under the ID.
+type SanitizePolicy struct {
+	Types   []ErrType // ServerErrTypes and any other 5xx status if nil
+	Message string    // DefaultSanitizedMessage if empty
+}
+
+func (sp *SanitizePolicy) applies(e *HTTPErr) bool {
+	types := sp.Types
+	if types == nil {
+		if e.ErrDoc.Code >= 500 {
+			return true
+		}
+		types = ServerErrTypes
+	}
+	for _, errType := range types {
+		if e.errType() == errType {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitize masks every message of a copied document, which must have an ID
+func (sp *SanitizePolicy) sanitize(errDoc HTTPErrDoc) HTTPErrDoc {
+	msg := sp.Message
+	if msg == "" {
+		msg = DefaultSanitizedMessage
+	}
+	msg = fmt.Sprintf("%s: %s", msg, errDoc.ID)
+
+	errDoc.Message = msg
+	innerErrs := make([]*InnerErr, 0, len(errDoc.Errors))
+	for _, iErr := range errDoc.Errors {
+		masked := *iErr
+		masked.Message = msg
+		innerErrs = append(innerErrs, &masked)
+	}
+	errDoc.Errors = innerErrs
+
+	// DebugInfo is for internal use
+	var details Details
+	for _, d := range errDoc.Details {
+		if d.TypeURL() != DebugInfoType {
+			details = append(details, d)
+		}
+	}
+	errDoc.Details = details
+	return errDoc
+}
@@ -0,0 +1,96 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"golang.org/x/xerrors"
+)
+
+func TestSanitizePolicy0(t *testing.T) {
+	var loggedID string
+	var logged *HTTPErr
+	rd := &Renderer{
+		Sanitize: &SanitizePolicy{},
+		Log: func(id string, e *HTTPErr) {
+			loggedID = id
+			logged = e
+		}}
+
+	cause := xerrors.New("pq: relation \"users\" does not exist")
+	errRes := NewHTTPErr(InternalServerError,
+		NewInnerErr("fooService", "db", "", "", "pq: relation \"users\" does not exist", cause)).WithDetails(
+		NewDebugInfo(nil, "SELECT * FROM users"))
+
+	res := rd.Prepare(nil, errRes)
+	bJSON, err := json.Marshal(res)
+	if err != nil {
+		t.Fatalf("Failed to marshal an err object: %+v\n", err)
+	}
+
+	if strings.Contains(string(bJSON), "pq:") || strings.Contains(string(bJSON), "SELECT") {
+		t.Fatalf("Message was leaked: %s\n", bJSON)
+	}
+	if res.ErrDoc.ID != sampleID || !strings.HasSuffix(res.ErrDoc.Message, res.ErrDoc.ID) ||
+		!strings.HasSuffix(res.ErrDoc.Errors[0].Message, res.ErrDoc.ID) {
+		t.Fatalf("Invalid error ID: %s\n", bJSON)
+	}
+
+	if loggedID != res.ErrDoc.ID || logged != errRes {
+		t.Fatalf("Original was not logged: %s, %+v\n", loggedID, logged)
+	}
+	if errRes.ErrDoc.Errors[0].Message != "pq: relation \"users\" does not exist" {
+		t.Fatal("Original HTTPErr was modified")
+	}
+}
+
+func TestSanitizePolicy1(t *testing.T) {
+	// Client errors are kept
+	rd := &Renderer{Sanitize: &SanitizePolicy{}}
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", nil))
+
+	res := rd.Prepare(nil, errRes)
+	if res.ErrDoc.Message != "Invalid argument" || res.ErrDoc.Errors[0].Message != "Passed id is invalid" {
+		t.Fatalf("Client error was sanitized: %s, %s\n", res.ErrDoc.Message, res.ErrDoc.Errors[0].Message)
+	}
+
+	// Configured types and message
+	rd = &Renderer{Sanitize: &SanitizePolicy{Types: []ErrType{Conflict}, Message: "Conflicted"}}
+	res = rd.Prepare(nil, NewHTTPErr(Conflict))
+	if !strings.HasPrefix(res.ErrDoc.Message, "Conflicted: ") {
+		t.Fatalf("Invalid message: %s\n", res.ErrDoc.Message)
+	}
+}
+
+func TestSanitizePolicy2(t *testing.T) {
+	// Types are not set by NewHTTPErr
+	rd := &Renderer{
+		Sanitize: &SanitizePolicy{}}
+
+	literal := &HTTPErr{
+		ErrDoc: HTTPErrDoc{
+			Code:   503,
+			Errors: []*InnerErr{&InnerErr{Message: "secret"}}}}
+	unmarshalled := &HTTPErr{}
+	err := json.Unmarshal([]byte(`{"error":{"errors":[{"message":"secret"}],"code":500,"message":"secret"}}`),
+		unmarshalled)
+	if err != nil {
+		t.Fatalf("Failed to unmarshal an err json: %+v\n", err)
+	}
+
+	for _, e := range []*HTTPErr{literal, unmarshalled} {
+		res, err := rd.Render(nil, JSONEncoder, e)
+		if err != nil {
+			t.Fatalf("Failed to render: %+v\n", err)
+		}
+		if strings.Contains(string(res.Body), "secret") {
+			t.Fatalf("Not masked: %s\n", res.Body)
+		}
+		// Missing IDs are generated by ErrIDGenerator
+		if !strings.Contains(string(res.Body), `"id":"`+sampleID+`"`) {
+			t.Fatalf("Invalid error ID: %s\n", res.Body)
+		}
+	}
+}
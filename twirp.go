@@ -0,0 +1,226 @@
+package xerrorz
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Based on https://twitchtv.github.io/twirp/docs/spec_v7.html#error-codes
+const (
+	TwirpCanceled           = "canceled"
+	TwirpUnknown            = "unknown"
+	TwirpInvalidArgument    = "invalid_argument"
+	TwirpMalformed          = "malformed"
+	TwirpDeadlineExceeded   = "deadline_exceeded"
+	TwirpNotFound           = "not_found"
+	TwirpBadRoute           = "bad_route"
+	TwirpAlreadyExists      = "already_exists"
+	TwirpPermissionDenied   = "permission_denied"
+	TwirpUnauthenticated    = "unauthenticated"
+	TwirpResourceExhausted  = "resource_exhausted"
+	TwirpFailedPrecondition = "failed_precondition"
+	TwirpAborted            = "aborted"
+	TwirpOutOfRange         = "out_of_range"
+	TwirpUnimplemented      = "unimplemented"
+	TwirpInternal           = "internal"
+	TwirpUnavailable        = "unavailable"
+	TwirpDataLoss           = "dataloss"
+)
+
+type TwirpErr struct {
+	Code string            `json:"code"`
+	Msg  string            `json:"msg"`
+	Meta map[string]string `json:"meta,omitempty"` // InnerErrs flattened as "errors.{i}.{field}"
+}
+
+// TwirpEncoder renders Twirp errors. The response status follows the Twirp code, not ErrDoc.Code.
+type TwirpEncoder struct{}
+
+func (TwirpEncoder) ContentType() string {
+	return "application/json"
+}
+
+func (TwirpEncoder) Status(e *HTTPErr) int {
+	return TwirpStatus(TwirpCode(e.errType()))
+}
+
+func (enc TwirpEncoder) Encode(w io.Writer, e *HTTPErr) error {
+	bJSON, err := json.Marshal(NewTwirpErr(e))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bJSON)
+	return err
+}
+
+// Decode decodes a Twirp error json
+func (TwirpEncoder) Decode(b []byte) (*HTTPErr, error) {
+	var t TwirpErr
+	if err := json.Unmarshal(b, &t); err != nil {
+		return nil, err
+	}
+	return t.HTTPErr(), nil
+}
+
+func NewTwirpErr(e *HTTPErr) *TwirpErr {
+	res := &TwirpErr{
+		Code: TwirpCode(e.errType()),
+		Msg:  e.ErrDoc.Message}
+	for i, iErr := range e.ErrDoc.Errors {
+		if res.Meta == nil {
+			res.Meta = map[string]string{}
+		}
+		prefix := fmt.Sprintf("errors.%d.", i)
+		res.Meta[prefix+"domain"] = iErr.Domain
+		res.Meta[prefix+"reason"] = iErr.Reason
+		res.Meta[prefix+"location"] = iErr.Location
+		res.Meta[prefix+"locationType"] = iErr.LocationType
+		res.Meta[prefix+"message"] = iErr.Message
+	}
+	return res
+}
+
+// HTTPErr maps back into an HTTPErr, restoring InnerErrs from meta
+func (t *TwirpErr) HTTPErr() *HTTPErr {
+	errType, ok := twirpErrTypes[t.Code]
+	if !ok {
+		errType = InternalServerError
+	}
+
+	iErrs := map[int]*InnerErr{}
+	for k, v := range t.Meta {
+		fields := strings.SplitN(k, ".", 3)
+		if len(fields) != 3 || fields[0] != "errors" {
+			continue
+		}
+		i, err := strconv.Atoi(fields[1])
+		if err != nil || i < 0 {
+			continue
+		}
+		iErr, ok := iErrs[i]
+		if !ok {
+			iErr = &InnerErr{}
+			iErrs[i] = iErr
+		}
+		switch fields[2] {
+		case "domain":
+			iErr.Domain = v
+		case "reason":
+			iErr.Reason = v
+		case "location":
+			iErr.Location = v
+		case "locationType":
+			iErr.LocationType = v
+		case "message":
+			iErr.Message = v
+		}
+	}
+	indices := []int{}
+	for i := range iErrs {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	innerErrs := []*InnerErr{}
+	for _, i := range indices {
+		innerErrs = append(innerErrs, iErrs[i])
+	}
+
+	return &HTTPErr{
+		ErrDoc: HTTPErrDoc{
+			Errors:  innerErrs,
+			Code:    errs[errType].Code,
+			Message: t.Msg},
+		Type: errType}
+}
+
+func TwirpCode(errType ErrType) string {
+	if code, ok := twirpCodes[errType]; ok {
+		return code
+	}
+	return TwirpUnknown
+}
+
+func TwirpStatus(code string) int {
+	if status, ok := twirpStatuses[code]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+var twirpCodes = map[ErrType]string{
+	BadRequest:                   TwirpInvalidArgument,
+	InvalidAltVaule:              TwirpInvalidArgument,
+	InvalidArgument:              TwirpInvalidArgument,
+	InvalidParameter:             TwirpInvalidArgument,
+	ParseError:                   TwirpMalformed,
+	Required:                     TwirpInvalidArgument,
+	TurnedDown:                   TwirpBadRoute,
+	AuthenticationError:          TwirpUnauthenticated,
+	NotAuthenticated:             TwirpUnauthenticated,
+	NotAuthorized:                TwirpPermissionDenied,
+	AccountDisabled:              TwirpPermissionDenied,
+	CountryBlocked:               TwirpPermissionDenied,
+	Forbidden:                    TwirpPermissionDenied,
+	InsufficientPermissions:      TwirpPermissionDenied,
+	SSLRequired:                  TwirpPermissionDenied,
+	NotFound:                     TwirpNotFound,
+	MethodNotAllowed:             TwirpBadRoute,
+	Conflict:                     TwirpAlreadyExists,
+	Gone:                         TwirpNotFound,
+	LengthRequired:               TwirpMalformed,
+	ConditionNotMet:              TwirpFailedPrecondition,
+	PayloadTooLarge:              TwirpResourceExhausted,
+	RequestedRangeNotSatisfiable: TwirpOutOfRange,
+	RateLimitExceeded:            TwirpResourceExhausted,
+	UserRateLimitExceeded:        TwirpResourceExhausted,
+	InternalServerError:          TwirpInternal,
+	BadGateway:                   TwirpUnavailable,
+	ServiceUnavailable:           TwirpUnavailable,
+}
+
+var twirpErrTypes = map[string]ErrType{
+	TwirpCanceled:           BadRequest,
+	TwirpUnknown:            InternalServerError,
+	TwirpInvalidArgument:    InvalidArgument,
+	TwirpMalformed:          ParseError,
+	TwirpDeadlineExceeded:   ServiceUnavailable,
+	TwirpNotFound:           NotFound,
+	TwirpBadRoute:           NotFound,
+	TwirpAlreadyExists:      Conflict,
+	TwirpPermissionDenied:   Forbidden,
+	TwirpUnauthenticated:    NotAuthenticated,
+	TwirpResourceExhausted:  RateLimitExceeded,
+	TwirpFailedPrecondition: ConditionNotMet,
+	TwirpAborted:            Conflict,
+	TwirpOutOfRange:         RequestedRangeNotSatisfiable,
+	TwirpUnimplemented:      MethodNotAllowed,
+	TwirpInternal:           InternalServerError,
+	TwirpUnavailable:        ServiceUnavailable,
+	TwirpDataLoss:           InternalServerError,
+}
+
+var twirpStatuses = map[string]int{
+	TwirpCanceled:           http.StatusRequestTimeout,
+	TwirpUnknown:            http.StatusInternalServerError,
+	TwirpInvalidArgument:    http.StatusBadRequest,
+	TwirpMalformed:          http.StatusBadRequest,
+	TwirpDeadlineExceeded:   http.StatusRequestTimeout,
+	TwirpNotFound:           http.StatusNotFound,
+	TwirpBadRoute:           http.StatusNotFound,
+	TwirpAlreadyExists:      http.StatusConflict,
+	TwirpPermissionDenied:   http.StatusForbidden,
+	TwirpUnauthenticated:    http.StatusUnauthorized,
+	TwirpResourceExhausted:  http.StatusTooManyRequests,
+	TwirpFailedPrecondition: http.StatusPreconditionFailed,
+	TwirpAborted:            http.StatusConflict,
+	TwirpOutOfRange:         http.StatusBadRequest,
+	TwirpUnimplemented:      http.StatusNotImplemented,
+	TwirpInternal:           http.StatusInternalServerError,
+	TwirpUnavailable:        http.StatusServiceUnavailable,
+	TwirpDataLoss:           http.StatusInternalServerError,
+}
@@ -0,0 +1,103 @@
+package xerrorz
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+)
+
+const sampleTwirpJSON = `
+{
+	"code": "invalid_argument",
+	"msg": "Invalid argument",
+	"meta": {
+	  "errors.0.domain": "fooService",
+	  "errors.0.reason": "invalidArgument",
+	  "errors.0.location": "id",
+	  "errors.0.locationType": "requestBody",
+	  "errors.0.message": "Passed id is invalid",
+	  "errors.1.domain": "fooService",
+	  "errors.1.reason": "invalidArgument",
+	  "errors.1.location": "name",
+	  "errors.1.locationType": "requestBody",
+	  "errors.1.message": "Passed name is invalid"
+	}
+}`
+
+func TestTwirpEncoder0(t *testing.T) {
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", nil),
+		NewInnerErr("fooService", "invalidArgument", "name", "requestBody", "Passed name is invalid", nil))
+
+	var buf bytes.Buffer
+	if err := (TwirpEncoder{}).Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	assertJSONEqual(t, buf.Bytes(), sampleTwirpJSON)
+}
+
+func TestTwirpEncoder1(t *testing.T) {
+	errRes, err := (TwirpEncoder{}).Decode([]byte(sampleTwirpJSON))
+	if err != nil {
+		t.Fatalf("Failed to decode an err json: %+v\n", err)
+	}
+	if errRes.Type != InvalidArgument || errRes.ErrDoc.Code != 400 {
+		t.Fatalf("Invalid type or status: %s, %d\n", errRes.Type, errRes.ErrDoc.Code)
+	}
+	if len(errRes.ErrDoc.Errors) != 2 ||
+		errRes.ErrDoc.Errors[0].Location != "id" || errRes.ErrDoc.Errors[1].Location != "name" {
+		t.Fatalf("Invalid inner errors: %+v\n", errRes.ErrDoc.Errors)
+	}
+}
+
+func TestTwirpEncoder2(t *testing.T) {
+	// Status follows the Twirp code
+	enc := TwirpEncoder{}
+	if status := enc.Status(NewHTTPErr(MethodNotAllowed)); status != http.StatusNotFound {
+		t.Fatalf("Invalid status: %d\n", status)
+	}
+	if status := enc.Status(NewHTTPErr(RateLimitExceeded)); status != http.StatusTooManyRequests {
+		t.Fatalf("Invalid status: %d\n", status)
+	}
+	for errType := range errs {
+		if _, ok := twirpStatuses[TwirpCode(errType)]; !ok {
+			t.Fatalf("No Twirp code for %s\n", errType)
+		}
+	}
+	for code := range twirpStatuses {
+		if _, ok := twirpErrTypes[code]; !ok {
+			t.Fatalf("No ErrType for %s\n", code)
+		}
+	}
+}
+
+func TestTwirpStatus0(t *testing.T) {
+	// https://twitchtv.github.io/twirp/docs/spec_v7.html#error-codes
+	spec := []struct {
+		code   string
+		status int
+	}{
+		{"canceled", 408},
+		{"unknown", 500},
+		{"invalid_argument", 400},
+		{"malformed", 400},
+		{"deadline_exceeded", 408},
+		{"not_found", 404},
+		{"bad_route", 404},
+		{"already_exists", 409},
+		{"permission_denied", 403},
+		{"unauthenticated", 401},
+		{"resource_exhausted", 429},
+		{"failed_precondition", 412},
+		{"aborted", 409},
+		{"out_of_range", 400},
+		{"unimplemented", 501},
+		{"internal", 500},
+		{"unavailable", 503},
+		{"dataloss", 500}}
+	for _, s := range spec {
+		if status := TwirpStatus(s.code); status != s.status {
+			t.Fatalf("Invalid status of %s: %d\n", s.code, status)
+		}
+	}
+}
@@ -1,6 +1,9 @@
 package xerrorz
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/xml"
 	"fmt"
 	"net/http"
 
@@ -8,26 +11,33 @@ import (
 )
 
 type HTTPErr struct {
-	ErrDoc HTTPErrDoc `json:"error"`
+	ErrDoc  HTTPErrDoc    `json:"error"`
+	Type    ErrType       `json:"-"`
+	Headers StatusHeaders `json:"-"`
 
 	frame xerrors.Frame `json:"-"`
 }
 
 type HTTPErrDoc struct {
-	Errors  []*InnerErr `json:"errors"`
-	Code    int         `json:"code" example:"429"`
-	Message string      `json:"message" example:"Rate Limit Exceeded"`
+	Errors     []*InnerErr `json:"errors" xml:"errors>error"`
+	Code       int         `json:"code" xml:"code" example:"429"`
+	Message    string      `json:"message" xml:"message" example:"Rate Limit Exceeded"`
+	Details    Details     `json:"details,omitempty" xml:"-"`                       // Typed details such as ErrorInfo, see details.go
+	ID         string      `json:"id,omitempty" xml:"id,omitempty"`                 // Generated by ErrIDGenerator to find logs
+	RequestID  string      `json:"requestId,omitempty" xml:"requestId,omitempty"`   // Picked up from X-Request-ID or traceparent
+	Debug      *DebugDoc   `json:"debug,omitempty" xml:"-"`                         // Only rendered if a DebugPolicy allows
+	DebugToken string      `json:"debugToken,omitempty" xml:"debugToken,omitempty"` // Encrypted causes and frames for support
 
 	frame xerrors.Frame `json:"-"`
 }
 
 type InnerErr struct {
-	Domain       string `json:"domain" example:"usage"`                // global, {yourService}, usage,...
-	Reason       string `json:"reason" example:"rateLimitExceeded"`    // invalidParameter, required,...
-	Location     string `json:"location" example:""`                   // Authorization, {paramName},...
-	LocationType string `json:"locationType" example:""`               // header, parameter,...
-	Message      string `json:"message" example:"Rate Limit Exceeded"` // {description}
-	Cause        error  `json:"-"`                                     // For error reporting
+	Domain       string `json:"domain" xml:"domain" example:"usage"`                 // global, {yourService}, usage,...
+	Reason       string `json:"reason" xml:"reason" example:"rateLimitExceeded"`     // invalidParameter, required,...
+	Location     string `json:"location" xml:"location" example:""`                  // Authorization, {paramName},...
+	LocationType string `json:"locationType" xml:"locationType" example:""`          // header, parameter,...
+	Message      string `json:"message" xml:"message" example:"Rate Limit Exceeded"` // {description}
+	Cause        error  `json:"-" xml:"-"`                                           // For error reporting
 
 	frame xerrors.Frame `json:"-"`
 }
@@ -41,7 +51,11 @@ func (e HTTPErr) Format(s fmt.State, v rune) {
 }
 
 func (e HTTPErr) FormatError(p xerrors.Printer) error {
-	p.Print(e.Error())
+	if e.ErrDoc.ID != "" {
+		p.Printf("[HTTP Status %d] %s (ID: %s)\n", e.ErrDoc.Code, e.ErrDoc.Message, e.ErrDoc.ID)
+	} else {
+		p.Print(e.Error())
+	}
 	e.frame.Format(p)
 	return e.ErrDoc
 }
@@ -50,6 +64,23 @@ func (e HTTPErr) Unwrap() error {
 	return e.ErrDoc
 }
 
+// MarshalXML encodes the document as an `error` element like the error json
+func (e HTTPErr) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "error"}
+	return enc.EncodeElement(e.ErrDoc, start)
+}
+
+func (e *HTTPErr) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	if err := dec.DecodeElement(&e.ErrDoc, &start); err != nil {
+		return err
+	}
+	if e.ErrDoc.Errors == nil {
+		e.ErrDoc.Errors = []*InnerErr{}
+	}
+	e.Type = errTypeOf(e.ErrDoc.Code, e.ErrDoc.Message)
+	return nil
+}
+
 func (e HTTPErrDoc) Error() string {
 	return e.Message
 }
@@ -98,11 +129,28 @@ func (e InnerErr) Error() string {
 	return e.Message
 }
 
+// ErrIDGenerator generates the ID of every HTTPErr. No ID is given if nil, though sanitized errors get RandomErrID ones.
+// Configure it before serving requests.
+var ErrIDGenerator = RandomErrID
+
+// RandomErrID generates a random 128-bit hex
+func RandomErrID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic("Failed to generate an error ID")
+	}
+	return hex.EncodeToString(b)
+}
+
 func NewHTTPErr(errType ErrType, innerErrs ...*InnerErr) *HTTPErr {
 	errDoc := errs[errType]
 	errDoc.frame = xerrors.Caller(0)
+	if ErrIDGenerator != nil {
+		errDoc.ID = ErrIDGenerator()
+	}
 	res := &HTTPErr{
 		ErrDoc: errDoc,
+		Type:   errType,
 		frame:  xerrors.Caller(1)}
 	if innerErrs != nil {
 		res.ErrDoc.Errors = innerErrs
@@ -187,6 +235,102 @@ const (
 	ServiceUnavailable
 )
 
+func (t ErrType) String() string {
+	if name, ok := errTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("ErrType(%d)", uint(t))
+}
+
+// ParseErrType looks up an ErrType by its name such as "InvalidArgument"
+func ParseErrType(name string) (ErrType, bool) {
+	for errType, n := range errTypeNames {
+		if n == name {
+			return errType, true
+		}
+	}
+	return 0, false
+}
+
+// ErrTypeForStatus returns the most generic ErrType for an http status code
+func ErrTypeForStatus(code int) ErrType {
+	if errType, ok := statusErrTypes[code]; ok {
+		return errType
+	}
+	if code >= http.StatusInternalServerError {
+		return InternalServerError
+	}
+	return BadRequest
+}
+
+// errType is Type, or resolved from the status and the message if Type may not be set by NewHTTPErr,
+// such as HTTPErrs of literals or json.Unmarshal whose zero value is BadRequest
+func (e *HTTPErr) errType() ErrType {
+	if e.Type != BadRequest {
+		return e.Type
+	}
+	return errTypeOf(e.ErrDoc.Code, e.ErrDoc.Message)
+}
+
+// errTypeOf finds the preset exactly matching a document, or the one for its status code
+func errTypeOf(code int, message string) ErrType {
+	for errType, errDoc := range errs {
+		if errDoc.Code == code && errDoc.Message == message {
+			return errType
+		}
+	}
+	return ErrTypeForStatus(code)
+}
+
+var errTypeNames = map[ErrType]string{
+	BadRequest:                   "BadRequest",
+	InvalidAltVaule:              "InvalidAltValue",
+	InvalidArgument:              "InvalidArgument",
+	InvalidParameter:             "InvalidParameter",
+	ParseError:                   "ParseError",
+	Required:                     "Required",
+	TurnedDown:                   "TurnedDown",
+	AuthenticationError:          "AuthenticationError",
+	NotAuthenticated:             "NotAuthenticated",
+	NotAuthorized:                "NotAuthorized",
+	AccountDisabled:              "AccountDisabled",
+	CountryBlocked:               "CountryBlocked",
+	Forbidden:                    "Forbidden",
+	InsufficientPermissions:      "InsufficientPermissions",
+	SSLRequired:                  "SSLRequired",
+	NotFound:                     "NotFound",
+	MethodNotAllowed:             "MethodNotAllowed",
+	Conflict:                     "Conflict",
+	Gone:                         "Gone",
+	LengthRequired:               "LengthRequired",
+	ConditionNotMet:              "ConditionNotMet",
+	PayloadTooLarge:              "PayloadTooLarge",
+	RequestedRangeNotSatisfiable: "RequestedRangeNotSatisfiable",
+	RateLimitExceeded:            "RateLimitExceeded",
+	UserRateLimitExceeded:        "UserRateLimitExceeded",
+	InternalServerError:          "InternalServerError",
+	BadGateway:                   "BadGateway",
+	ServiceUnavailable:           "ServiceUnavailable",
+}
+
+var statusErrTypes = map[int]ErrType{
+	http.StatusBadRequest:                   BadRequest,
+	http.StatusUnauthorized:                 AuthenticationError,
+	http.StatusForbidden:                    Forbidden,
+	http.StatusNotFound:                     NotFound,
+	http.StatusMethodNotAllowed:             MethodNotAllowed,
+	http.StatusConflict:                     Conflict,
+	http.StatusGone:                         Gone,
+	http.StatusLengthRequired:               LengthRequired,
+	http.StatusPreconditionFailed:           ConditionNotMet,
+	http.StatusRequestEntityTooLarge:        PayloadTooLarge,
+	http.StatusRequestedRangeNotSatisfiable: RequestedRangeNotSatisfiable,
+	http.StatusTooManyRequests:              RateLimitExceeded,
+	http.StatusInternalServerError:          InternalServerError,
+	http.StatusBadGateway:                   BadGateway,
+	http.StatusServiceUnavailable:           ServiceUnavailable,
+}
+
 var errs = map[ErrType]HTTPErrDoc{
 	BadRequest: HTTPErrDoc{
 		Code:    http.StatusBadRequest,
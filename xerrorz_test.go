@@ -1,7 +1,9 @@
 package xerrorz
 
 import (
+	"bytes"
 	"encoding/json"
+	"encoding/xml"
 	"io"
 	"reflect"
 	"testing"
@@ -9,6 +11,12 @@ import (
 	"golang.org/x/xerrors"
 )
 
+const sampleID = "0123456789abcdef0123456789abcdef"
+
+func init() {
+	ErrIDGenerator = func() string { return sampleID }
+}
+
 const sampleJSON = `
 {
 	"error": {
@@ -29,7 +37,8 @@ const sampleJSON = `
 		}
 	  ],
 	  "code": 400,
-	  "message": "Invalid argument"
+	  "message": "Invalid argument",
+	  "id": "0123456789abcdef0123456789abcdef"
 	}
 }`
 
@@ -99,3 +108,81 @@ func TestContents0(t *testing.T) {
 		t.Fatal("Invalid status code")
 	}
 }
+
+func TestErrType0(t *testing.T) {
+	if InvalidArgument.String() != "InvalidArgument" {
+		t.Fatalf("Invalid name: %s\n", InvalidArgument)
+	}
+	if errType, ok := ParseErrType("InvalidAltValue"); !ok || errType != InvalidAltVaule {
+		t.Fatal("Failed to parse a name")
+	}
+	if _, ok := ParseErrType("Unknown"); ok {
+		t.Fatal("Unknown name was parsed")
+	}
+}
+
+func TestErrType1(t *testing.T) {
+	if ErrTypeForStatus(404) != NotFound {
+		t.Fatal("Invalid ErrType for 404")
+	}
+	if ErrTypeForStatus(418) != BadRequest {
+		t.Fatal("Invalid ErrType for 418")
+	}
+	if ErrTypeForStatus(504) != InternalServerError {
+		t.Fatal("Invalid ErrType for 504")
+	}
+	for errType, errDoc := range errs {
+		if _, ok := errTypeNames[errType]; !ok {
+			t.Fatalf("No name for %d\n", errType)
+		}
+		if errTypeOf(errDoc.Code, errDoc.Message) != errType {
+			t.Fatalf("Failed to identify %s\n", errType)
+		}
+	}
+}
+
+const sampleXML = `<?xml version="1.0" encoding="UTF-8"?>
+<error><errors><error><domain>fooService</domain><reason>invalidArgument</reason><location>id</location><locationType>requestBody</locationType><message>Passed id is invalid</message></error></errors><code>400</code><message>Invalid argument</message><id>0123456789abcdef0123456789abcdef</id></error>`
+
+func TestXML0(t *testing.T) {
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", io.ErrNoProgress))
+
+	var buf bytes.Buffer
+	if err := XMLEncoder.Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	if buf.String() != sampleXML {
+		t.Fatalf("Inconsistent xml was generated: %s\n", buf.String())
+	}
+}
+
+func TestXML1(t *testing.T) {
+	var errRes HTTPErr
+	if err := xml.Unmarshal([]byte(sampleXML), &errRes); err != nil {
+		t.Fatalf("Failed to decode an err xml: %+v\n", err)
+	}
+	if errRes.Type != InvalidArgument || errRes.ErrDoc.Code != 400 {
+		t.Fatalf("Invalid type or status: %s, %d\n", errRes.Type, errRes.ErrDoc.Code)
+	}
+	if len(errRes.ErrDoc.Errors) != 1 || errRes.ErrDoc.Errors[0].Location != "id" {
+		t.Fatalf("Invalid inner errors: %+v\n", errRes.ErrDoc.Errors)
+	}
+}
+
+func TestText0(t *testing.T) {
+	errRes := NewHTTPErr(InvalidArgument,
+		NewInnerErr("fooService", "invalidArgument", "id", "requestBody", "Passed id is invalid", nil),
+		NewInnerErr("", "", "", "", "Something wrong", nil))
+
+	var buf bytes.Buffer
+	if err := TextEncoder.Encode(&buf, errRes); err != nil {
+		t.Fatalf("Failed to encode an err object: %+v\n", err)
+	}
+	expected := "400 Invalid argument\n" +
+		"- fooService.invalidArgument: Passed id is invalid (requestBody: id)\n" +
+		"- Something wrong\n"
+	if buf.String() != expected {
+		t.Fatalf("Inconsistent text was generated: %s\n", buf.String())
+	}
+}